@@ -0,0 +1,359 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeKey is the key of the mappings merged into the one holding it, like `<<: *base`.
+const mergeKey = "<<"
+
+// resolveAlias returns the node an alias points to, or node itself.
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// isMergeKey tells if key is a merge key. Their tag is dropped when reading, see implicitMergeKeys.
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.Value == mergeKey && (key.Tag == "!!merge" || key.Tag == "" && key.Style == 0)
+}
+
+// mergedMappings returns the mappings merged into mapping, in order of precedence.
+func mergedMappings(mapping *yaml.Node) []*yaml.Node {
+	merged := make([]*yaml.Node, 0)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if !isMergeKey(mapping.Content[i]) {
+			continue
+		}
+
+		switch value := resolveAlias(mapping.Content[i+1]); value.Kind {
+		case yaml.MappingNode:
+			merged = append(merged, value)
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item = resolveAlias(item); item.Kind == yaml.MappingNode {
+					merged = append(merged, item)
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// mergedEntry returns the key and value of name merged into mapping, and the mapping holding them.
+func mergedEntry(mapping *yaml.Node, name string) (holder, key, value *yaml.Node) {
+	for _, m := range mergedMappings(mapping) {
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if m.Content[i].Kind == yaml.ScalarNode && m.Content[i].Value == name && !isMergeKey(m.Content[i]) {
+				return m, m.Content[i], m.Content[i+1]
+			}
+		}
+		if holder, key, value = mergedEntry(m, name); key != nil {
+			return
+		}
+	}
+	return nil, nil, nil
+}
+
+// mappingEntries returns the keys and values of mapping, followed by the ones merged into it
+// that it does not override.
+func mappingEntries(mapping *yaml.Node) []*yaml.Node {
+	entries := make([]*yaml.Node, 0, len(mapping.Content))
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if !isMergeKey(mapping.Content[i]) {
+			entries = append(entries, mapping.Content[i], mapping.Content[i+1])
+			seen[mapping.Content[i].Value] = true
+		}
+	}
+
+	for _, m := range mergedMappings(mapping) {
+		merged := mappingEntries(m)
+		for i := 0; i+1 < len(merged); i += 2 {
+			if !seen[merged[i].Value] {
+				entries = append(entries, merged[i], merged[i+1])
+				seen[merged[i].Value] = true
+			}
+		}
+	}
+
+	return entries
+}
+
+// implicitMergeKeys drops the tag of the merge keys of node, which yaml would write as `!!merge <<`.
+// It is called on decoded documents, before they are cached and shared with readers.
+func implicitMergeKeys(node *yaml.Node) {
+	walkNodes(node, func(n *yaml.Node) bool {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if isMergeKey(n.Content[i]) {
+					n.Content[i].Tag = ""
+				}
+			}
+		}
+		return true
+	})
+}
+
+// detachedCopy returns a copy of node without anchors, so it can replace an alias to node.
+// Aliases inside the copy keep pointing to the anchors of the original.
+func detachedCopy(node *yaml.Node) *yaml.Node {
+	c := _cloneNode(resolveAlias(node), make(map[*yaml.Node]*yaml.Node))
+	walkNodes(c, func(n *yaml.Node) bool {
+		n.Anchor = ""
+		return true
+	})
+	return c
+}
+
+// detachAlias turns the alias node into a copy of the value it points to, keeping its comments.
+func detachAlias(node *yaml.Node) {
+	head, line, foot := node.HeadComment, node.LineComment, node.FootComment
+	*node = *detachedCopy(node)
+	node.HeadComment, node.LineComment, node.FootComment = head, line, foot
+}
+
+// walkNodes calls fn on node and its descendants in document order, without following aliases,
+// until fn returns false.
+func walkNodes(node *yaml.Node, fn func(*yaml.Node) bool) bool {
+	if !fn(node) {
+		return false
+	}
+	for _, c := range node.Content {
+		if !walkNodes(c, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// aliasesOf returns the aliases inside doc pointing to node or to one of its descendants.
+func aliasesOf(doc, node *yaml.Node) []*yaml.Node {
+	anchors := make(map[*yaml.Node]bool)
+	walkNodes(node, func(n *yaml.Node) bool {
+		if n.Anchor != "" {
+			anchors[n] = true
+		}
+		return true
+	})
+
+	aliases := make([]*yaml.Node, 0)
+	if len(anchors) == 0 {
+		return aliases
+	}
+
+	walkNodes(doc, func(n *yaml.Node) bool {
+		if n.Kind == yaml.AliasNode && anchors[n.Alias] && !isInside(n, node) {
+			aliases = append(aliases, n)
+		}
+		return true
+	})
+	return aliases
+}
+
+// checkUnaliased fails if removing or replacing node would leave aliases of the document without
+// their anchor. Aliases to kept, which stays in place, are fine.
+func (s *Seer) checkUnaliased(document string, node, kept *yaml.Node) error {
+	doc, ok := s.cachedDocument(document)
+	if !ok {
+		return nil
+	}
+
+	for _, alias := range aliasesOf(doc, node) {
+		if alias.Alias != kept {
+			return fmt.Errorf("anchor `%s` is still used by an alias", alias.Value)
+		}
+	}
+	return nil
+}
+
+// isInside tells if node is root or one of its descendants.
+func isInside(node, root *yaml.Node) bool {
+	return !walkNodes(root, func(n *yaml.Node) bool { return n != node })
+}
+
+// precedes tells if a comes before b in doc, as anchors must be defined before their aliases.
+func precedes(doc, a, b *yaml.Node) bool {
+	found := false
+	walkNodes(doc, func(n *yaml.Node) bool {
+		if n == a {
+			found = true
+		}
+		return n != a && n != b
+	})
+	return found
+}
+
+func validAnchor(name string) error {
+	if strings.ContainsAny(name, " \t\r\n,[]{}") {
+		return fmt.Errorf("invalid anchor name `%s`", name)
+	}
+	return nil
+}
+
+// SetAnchor names the value so other values of the document can point to it with SetAlias.
+// Renaming an anchor updates its aliases, and an empty name removes it if no alias uses it.
+func (n *Query) SetAnchor(name string) *Query {
+	if err := validAnchor(name); err != nil {
+		n.errors = append(n.errors, err)
+		return n
+	}
+
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			value:   name,
+			handler: opSetAnchor,
+		},
+	)
+	return n
+}
+
+// SetAlias replaces the value by an alias to the value of target, which must be anchored with
+// SetAnchor and come before it in the same document.
+//
+// Reads follow aliases and merge keys (`<<: *base`). A write through an alias or a merged key
+// does not change the anchored value: the alias, or the key, gets its own copy of the value first.
+func (n *Query) SetAlias(target *Query) *Query {
+	if target == nil {
+		n.errors = append(n.errors, errors.New("SetAlias() needs a target"))
+		return n
+	}
+
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			value:   target.Fork(),
+			handler: opSetAlias,
+		},
+	)
+	return n
+}
+
+func opSetAnchor(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to call SetAnchor(): %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.this == nil {
+		return path, nil, fmt.Errorf("failed to call SetAnchor(): %w", ErrNotDocument)
+	}
+
+	node := value.this
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) != 1 {
+			return path, nil, fmt.Errorf("failed to call SetAnchor() on an empty document: %w", ErrNotFound)
+		}
+		node = node.Content[0]
+	}
+
+	if node.Kind == yaml.AliasNode {
+		return path, nil, errors.New("an alias can not be anchored")
+	}
+
+	name := this.value.(string)
+	doc, _ := query.seer.cachedDocument(value.document)
+	aliases := make([]*yaml.Node, 0)
+	for _, alias := range aliasesOf(doc, node) {
+		if alias.Alias == node {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	if name == "" {
+		if len(aliases) > 0 {
+			return path, nil, fmt.Errorf("anchor `%s` is still used by %d aliases", node.Anchor, len(aliases))
+		}
+	} else if node.Anchor != name {
+		taken := false
+		walkNodes(doc, func(n *yaml.Node) bool {
+			taken = n.Anchor == name
+			return !taken
+		})
+		if taken {
+			return path, nil, fmt.Errorf("anchor `%s` is already defined", name)
+		}
+	}
+
+	query.seer.modified(value.document)
+
+	for _, alias := range aliases {
+		alias.Value = name
+	}
+	node.Anchor = name
+
+	return path, value, nil
+}
+
+func opSetAlias(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to call SetAlias(): %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.this == nil || value.parent == nil {
+		return path, nil, fmt.Errorf("failed to call SetAlias() outside a value: %w", ErrNotDocument)
+	}
+
+	target := this.value.(*Query)
+	if target.seer != query.seer {
+		return path, nil, errors.New("SetAlias() target belongs to another seer")
+	}
+
+	if target.hasGlob() {
+		return path, nil, errors.New("SetAlias() target can not have wildcards")
+	}
+
+	// the target is read under the lock of the query, which covers its document only
+	if strings.Join(target.lockedPath(), "/") != strings.Join(query.lockedPath(), "/") {
+		return path, nil, errors.New("aliases can only point to values of the same document")
+	}
+
+	q := target.reader()
+	q.held = true
+	_, anchored, err := q.run(navigation(target.ops))
+	if err != nil {
+		return path, nil, fmt.Errorf("resolving alias target %s failed with %w", target.String(), err)
+	}
+
+	if anchored == nil || anchored.parent == nil || anchored.document != value.document {
+		return path, nil, errors.New("aliases can only point to values of the same document")
+	}
+
+	anchor := resolveAlias(anchored.this)
+	if anchor.Anchor == "" {
+		return path, nil, fmt.Errorf("%s has no anchor, set one with SetAnchor()", target.String())
+	}
+
+	if isInside(value.this, anchor) {
+		return path, nil, errors.New("a value can not be an alias to itself")
+	}
+
+	if err = query.seer.checkUnaliased(value.document, value.this, nil); err != nil {
+		return path, nil, err
+	}
+
+	doc, _ := query.seer.cachedDocument(value.document)
+	if !precedes(doc, anchor, value.this) {
+		return path, nil, fmt.Errorf("anchor `%s` must come before its aliases", anchor.Anchor)
+	}
+
+	query.seer.modified(value.document)
+
+	node := value.this
+	*node = yaml.Node{
+		Kind:        yaml.AliasNode,
+		Value:       anchor.Anchor,
+		Alias:       anchor,
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+	}
+
+	return path, value, nil
+}
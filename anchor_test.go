@@ -0,0 +1,158 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestAnchors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("base: &base\n    color: red\n    size: 1\nref: *base\ncar:\n    <<: *base\n    size: 2\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	read := func() string {
+		assert.NilError(t, seer.Sync())
+		data, err := afero.ReadFile(fs, "/cars/car.yaml")
+		assert.NilError(t, err)
+		return string(data)
+	}
+
+	// reads follow aliases and merge keys
+	for path, expected := range map[string]string{
+		"cars/car.ref.color": "red",
+		"cars/car.car.color": "red",
+		"cars/car.car.size":  "2",
+	} {
+		var value string
+		assert.NilError(t, seer.Path(path).Value(&value), path)
+		assert.Equal(t, value, expected, path)
+	}
+
+	kind, err := seer.Path("cars/car.ref").Kind()
+	assert.NilError(t, err)
+	assert.Equal(t, kind, KindAlias)
+
+	matches, err := seer.Path("cars/car.car.*").Matches()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, matches.Names(), []string{"color", "size"})
+
+	// writes copy the anchored value first
+	assert.NilError(t, seer.Path("cars/car.ref.color").Set("blue").Commit())
+	assert.NilError(t, seer.Path("cars/car.car.color").Set("green").Commit())
+	assert.Equal(t, read(), "base: &base\n    color: red\n    size: 1\nref:\n    color: blue\n    size: 1\ncar:\n    <<: *base\n    size: 2\n    color: green\n")
+
+	// setting an anchored value keeps its aliases
+	assert.NilError(t, seer.Path("cars/car.base").Set(map[string]interface{}{"color": "black"}).Commit())
+	var color string
+	assert.NilError(t, seer.Path("cars/car.car.color").Value(&color))
+	assert.Equal(t, color, "green")
+	assert.NilError(t, seer.Path("cars/car.base").Set(map[string]interface{}{"color": "black", "wheels": 4}).Commit())
+	var wheels int
+	assert.NilError(t, seer.Path("cars/car.car.wheels").Value(&wheels))
+	assert.Equal(t, wheels, 4)
+
+	err = seer.Path("cars/car.base").Delete().Commit()
+	assert.ErrorContains(t, err, "anchor `base` is still used")
+
+	// authoring
+	assert.NilError(t, seer.Path("cars/car.wheel").Set(map[string]interface{}{"size": 17}).SetAnchor("wheel").Commit())
+	assert.NilError(t, seer.Path("cars/car.spare").SetAlias(seer.Path("cars/car.wheel")).Commit())
+	assert.Equal(t, read(), "base: &base\n    color: black\n    wheels: 4\nref:\n    color: blue\n    size: 1\ncar:\n    <<: *base\n    size: 2\n    color: green\nwheel: &wheel\n    size: 17\nspare: *wheel\n")
+
+	var size int
+	assert.NilError(t, seer.Path("cars/car.spare.size").Value(&size))
+	assert.Equal(t, size, 17)
+
+	// renaming an anchor updates its aliases
+	assert.NilError(t, seer.Path("cars/car.wheel").SetAnchor("rim").Commit())
+	assert.Equal(t, read(), "base: &base\n    color: black\n    wheels: 4\nref:\n    color: blue\n    size: 1\ncar:\n    <<: *base\n    size: 2\n    color: green\nwheel: &rim\n    size: 17\nspare: *rim\n")
+
+	err = seer.Path("cars/car.wheel").SetAnchor("").Commit()
+	assert.ErrorContains(t, err, "still used")
+
+	err = seer.Path("cars/car.wheel").SetAnchor("base").Commit()
+	assert.ErrorContains(t, err, "already defined")
+
+	err = seer.Path("cars/car.other").SetAlias(seer.Path("cars/car.ref")).Commit()
+	assert.ErrorContains(t, err, "has no anchor")
+
+	err = seer.Path("cars/car.base.color").SetAlias(seer.Path("cars/car.wheel")).Commit()
+	assert.ErrorContains(t, err, "must come before")
+
+	err = seer.Path("cars/car.wheel.size").SetAlias(seer.Path("cars/car.wheel")).Commit()
+	assert.ErrorContains(t, err, "alias to itself")
+
+	afero.WriteFile(fs, "/cars/van.yaml", []byte("wheel: &wheel {size: 15}\n"), 0640)
+	err = seer.Path("cars/car.other").SetAlias(seer.Path("cars/van.wheel")).Commit()
+	assert.ErrorContains(t, err, "same document")
+
+	err = seer.Path("cars/car.other").SetAnchor("a b").Commit()
+	assert.ErrorContains(t, err, "errors preventing commit")
+
+	assert.DeepEqual(t, seer.Dirty(), []string{})
+}
+
+func TestAnchorsDropped(t *testing.T) {
+	const fixture = "spec:\n    base: &b\n        x: 1\n    y: 2\nref: *b\n"
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/doc.yaml", []byte(fixture), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	// writers dropping an anchored value fail and leave the document as is
+	err = seer.Get("doc").Get("spec").Reconcile(map[string]int{"y": 3}).Commit()
+	assert.ErrorContains(t, err, "anchor `b` is still used")
+
+	err = seer.Get("doc").ApplyMergePatch([]byte(`{"spec": {"base": null}}`))
+	assert.ErrorContains(t, err, "anchor `b` is still used")
+
+	assert.NilError(t, seer.Sync())
+	data, err := afero.ReadFile(fs, "/doc.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), fixture)
+
+	// unless the alias goes first
+	assert.NilError(t, seer.Batch(
+		seer.Get("doc").Get("ref").Delete(),
+		seer.Get("doc").Get("spec").Reconcile(map[string]int{"y": 3}),
+	).Commit())
+	assert.NilError(t, seer.Sync())
+	data, err = afero.ReadFile(fs, "/doc.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "spec:\n    y: 3\n")
+}
+
+func TestAnchorsConcurrentSync(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("base: &base\n    color: red\ncar:\n    <<: *base\n    size: 2\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	assert.NilError(t, seer.Path("cars/car.car.size").Set(3).Commit())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			var color string
+			assert.Check(t, seer.Path("cars/car.car.color").Value(&color))
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		assert.NilError(t, seer.Path("cars/car.car.size").Set(i).Commit())
+		assert.NilError(t, seer.Sync())
+	}
+	<-done
+
+	data, err := afero.ReadFile(fs, "/cars/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "base: &base\n    color: red\ncar:\n    <<: *base\n    size: 49\n")
+}
@@ -1,10 +1,29 @@
 package seer
 
+// Commit applies all queries of the batch, or none of them: if a query fails,
+// the changes made by the queries before it are rolled back.
 func (b *Batch) Commit() error {
-	for _, q := range b.queries {
-		if err := q.Commit(); err != nil {
+	if b.tx == nil {
+		unlock, err := b.seer.lockAll()
+		if err != nil {
 			return err
 		}
+		defer unlock()
+	} else if b.tx.done {
+		return errTxDone
 	}
-	return nil
+
+	return b.seer.commitAll(b.queries)
+}
+
+// commitAll commits queries as a whole. Must be called with s.lock held.
+func (s *Seer) commitAll(queries []*Query) error {
+	s.begin()
+	for _, q := range queries {
+		if err := q.commit(); err != nil {
+			return s.abort(err)
+		}
+	}
+
+	return s.finish()
 }
@@ -0,0 +1,48 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestBatchRollback(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/existing.yaml", []byte("k: original\n"), 0640)
+	afero.WriteFile(fs, "/gone.yaml", []byte("k: kept\n"), 0640)
+	fs.Mkdir("/bad.yaml", 0750)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	err = seer.Batch(
+		seer.Get("a").Get("b").Document().Set(1),
+		seer.Get("existing").Get("k").Set("changed"),
+		seer.Get("gone").Delete(),
+		seer.Get("bad").Get("x").Set(1),
+	).Commit()
+	assert.Assert(t, err != nil)
+
+	_, err = fs.Stat("/a")
+	assert.Assert(t, err != nil)
+
+	data, err := afero.ReadFile(fs, "/gone.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "k: kept\n")
+
+	var val string
+	assert.NilError(t, seer.Get("existing").Get("k").Value(&val))
+	assert.Equal(t, val, "original")
+	assert.Equal(t, len(seer.Dirty()), 0)
+
+	err = seer.Batch(
+		seer.Get("a").Get("b").Document().Set(1),
+		seer.Get("existing").Get("k").Set("changed"),
+	).Commit()
+	assert.NilError(t, err)
+
+	assert.NilError(t, seer.Get("existing").Get("k").Value(&val))
+	assert.Equal(t, val, "changed")
+	assert.DeepEqual(t, seer.Dirty(), []string{"/a/b.yaml", "/existing.yaml"})
+}
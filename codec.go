@@ -0,0 +1,339 @@
+package seer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Codec reads and writes the documents of the files of a format. Documents are handled as yaml
+// nodes, so every format is queried the same way.
+type Codec interface {
+	// Decode parses the documents of data. An empty file should hold a single empty document.
+	Decode(data []byte) ([]*yaml.Node, error)
+	// Encode formats docs as the content of a file.
+	Encode(docs []*yaml.Node) ([]byte, error)
+}
+
+// MultiDocumentCodec is implemented by the codecs of formats holding many documents in a file,
+// like YAML. Documents can only be appended, with Index, to the files of such codecs.
+type MultiDocumentCodec interface {
+	Codec
+	// MultiDocument tells if a file can hold many documents.
+	MultiDocument() bool
+}
+
+// defaultCodecs are the codecs of the extensions not registered with RegisterCodec.
+var defaultCodecs = map[string]Codec{
+	".json": JSON(),
+	".toml": TOML(),
+}
+
+// RegisterCodec makes the Seer read and write the files with extension using codec, adding the
+// extension to the ones recognized if needed, after the others. Files with an extension without
+// codec are read as YAML.
+func RegisterCodec(extension string, codec Codec) Option {
+	return func(s *Seer) error {
+		if err := validExtension(extension); err != nil {
+			return err
+		}
+
+		if codec == nil {
+			return fmt.Errorf("no codec given for `%s`", extension)
+		}
+
+		if s.codecs == nil {
+			s.codecs = make(map[string]Codec)
+		}
+		s.codecs[extension] = codec
+
+		if !slices.Contains(s.extensions, extension) {
+			s.extensions = append(slices.Clone(s.extensions), extension)
+		}
+		return nil
+	}
+}
+
+// codecOf returns the codec of the document file at path.
+func (s *Seer) codecOf(path string) Codec {
+	ext := s.documentExtension(path)
+	if codec, ok := s.codecs[ext]; ok {
+		return codec
+	}
+	if codec, ok := defaultCodecs[ext]; ok {
+		return codec
+	}
+	return YAML()
+}
+
+type yamlCodec struct{}
+
+// YAML returns the codec of YAML files, used unless another one is registered for the extension.
+// Files hold many documents separated by `---`.
+func YAML() Codec {
+	return yamlCodec{}
+}
+
+func (yamlCodec) Decode(data []byte) ([]*yaml.Node, error) {
+	docs := make([]*yaml.Node, 0, 1)
+	yaml_decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		root_node := &yaml.Node{}
+		err := yaml_decoder.Decode(root_node)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		implicitMergeKeys(root_node)
+		docs = append(docs, root_node)
+	}
+
+	if len(docs) == 0 {
+		docs = append(docs, &yaml.Node{})
+	}
+
+	return docs, nil
+}
+
+func (yamlCodec) MultiDocument() bool {
+	return true
+}
+
+func (yamlCodec) Encode(docs []*yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type jsonCodec struct{}
+
+// JSON returns the codec of `.json` files. They hold a single document, written indented with
+// the order of its keys kept. Comments and anchors are lost.
+func JSON() Codec {
+	return jsonCodec{}
+}
+
+func (jsonCodec) Decode(data []byte) ([]*yaml.Node, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		// an empty document
+		return []*yaml.Node{{}}, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := readJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = dec.Token(); err == nil {
+		return nil, errors.New("a JSON file holds a single document")
+	} else if !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return []*yaml.Node{{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}}, nil
+}
+
+// readJSON reads the next value of dec as a node, keeping the order of keys. A key defined
+// twice keeps the position of the first one and the value of the last one, as encoding/json does.
+func readJSON(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '[' {
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				item, err := readJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, item)
+			}
+			_, err = dec.Token()
+			return node, err
+		}
+
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := readJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			if existing := childNode(node, key.(string)); existing != nil {
+				*existing = *value
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, value)
+			}
+		}
+		_, err = dec.Token()
+		return node, err
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(string(v), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(v)}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}, nil
+	}
+
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+func (jsonCodec) Encode(docs []*yaml.Node) ([]byte, error) {
+	if len(docs) != 1 {
+		return nil, fmt.Errorf("a JSON file holds a single document, not %d", len(docs))
+	}
+
+	var compact bytes.Buffer
+	if err := writeJSON(&compact, docs[0]); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+
+	return out.Bytes(), nil
+}
+
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case 0:
+		buf.WriteString("null")
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		entries := mappingEntries(node)
+		for i := 0; i+1 < len(entries); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(entries[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, entries[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		v, err := jsonValue(node)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+type tomlCodec struct{}
+
+// TOML returns the codec of `.toml` files. They hold a single document, whose keys are written
+// sorted. Comments and anchors are lost.
+func TOML() Codec {
+	return tomlCodec{}
+}
+
+func (tomlCodec) Decode(data []byte) ([]*yaml.Node, error) {
+	var value map[string]interface{}
+	if err := toml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{}
+	if len(value) == 0 {
+		// an empty document
+		return []*yaml.Node{doc}, nil
+	}
+
+	if err := doc.Encode(value); err != nil {
+		return nil, err
+	}
+	return []*yaml.Node{{Kind: yaml.DocumentNode, Content: []*yaml.Node{doc}}}, nil
+}
+
+func (tomlCodec) Encode(docs []*yaml.Node) ([]byte, error) {
+	if len(docs) != 1 {
+		return nil, fmt.Errorf("a TOML file holds a single document, not %d", len(docs))
+	}
+
+	doc := docs[0]
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+		doc = doc.Content[0]
+	}
+
+	var value interface{}
+	if doc.Kind != 0 && doc.Kind != yaml.DocumentNode {
+		if err := doc.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	if value == nil {
+		return []byte{}, nil
+	}
+
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("a TOML document is a table, not %T", value)
+	}
+
+	return toml.Marshal(value)
+}
@@ -0,0 +1,120 @@
+package seer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"gotest.tools/v3/assert"
+)
+
+// envCodec reads `KEY=value` lines, as a minimal custom format.
+type envCodec struct{}
+
+func (envCodec) Decode(data []byte) ([]*yaml.Node, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			mapping.Content = append(mapping.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+			)
+		}
+	}
+	return []*yaml.Node{{Kind: yaml.DocumentNode, Content: []*yaml.Node{mapping}}}, nil
+}
+
+func (envCodec) Encode(docs []*yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	mapping := docs[0].Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		fmt.Fprintf(&buf, "%s=%s\n", mapping.Content[i].Value, mapping.Content[i+1].Value)
+	}
+	return buf.Bytes(), nil
+}
+
+func TestCodecs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/app/package.json", []byte(`{"name": "app", "version": "1.0.0"}`), 0640)
+	afero.WriteFile(fs, "/app/escapes.json", []byte(`{"path": "a\/b", "tab": "\t\u00e9", "n": 1, "n": 2, "big": 1e3}`), 0640)
+	afero.WriteFile(fs, "/app/config.toml", []byte("name = \"app\"\n\n[server]\nport = 8080\n"), 0640)
+	afero.WriteFile(fs, "/app/deploy.yaml", []byte("replicas: 2\n"), 0640)
+	afero.WriteFile(fs, "/app/secrets.env", []byte("TOKEN=abc\n"), 0640)
+
+	seer, err := New(
+		VirtualFS(fs, "/"),
+		Extensions(".yaml", ".json"),
+		RegisterCodec(".toml", TOML()),
+		RegisterCodec(".env", envCodec{}),
+	)
+	assert.NilError(t, err)
+
+	read := func(path string) string {
+		assert.NilError(t, seer.Sync())
+		data, err := afero.ReadFile(fs, path)
+		assert.NilError(t, err)
+		return string(data)
+	}
+
+	app, err := seer.Get("app").List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, app, []string{"config", "deploy", "escapes", "package", "secrets"})
+
+	// JSON is read by a JSON parser, not as YAML
+	var escapes struct {
+		Path string  `yaml:"path"`
+		Tab  string  `yaml:"tab"`
+		N    int     `yaml:"n"`
+		Big  float64 `yaml:"big"`
+	}
+	assert.NilError(t, seer.Path("app/escapes").Value(&escapes))
+	assert.Equal(t, escapes.Path, "a/b")
+	assert.Equal(t, escapes.Tab, "\té")
+	assert.Equal(t, escapes.N, 2)
+	assert.Equal(t, escapes.Big, 1000.0)
+
+	for _, data := range []string{`{"a": }`, `{} {}`, `[1, 2`} {
+		_, err := JSON().Decode([]byte(data))
+		assert.Assert(t, err != nil, data)
+	}
+
+	// one query API over every format
+	var (
+		version  string
+		port     int
+		replicas int
+		token    string
+	)
+	assert.NilError(t, seer.Path("app/package.version").Value(&version))
+	assert.NilError(t, seer.Path("app/config.server.port").Value(&port))
+	assert.NilError(t, seer.Path("app/deploy.replicas").Value(&replicas))
+	assert.NilError(t, seer.Path("app/secrets.TOKEN").Value(&token))
+	assert.Equal(t, version, "1.0.0")
+	assert.Equal(t, port, 8080)
+	assert.Equal(t, replicas, 2)
+	assert.Equal(t, token, "abc")
+
+	// and every document is written back in its format
+	assert.NilError(t, seer.Path("app/package.version").Set("1.1.0").Commit())
+	assert.Equal(t, read("/app/package.json"), "{\n  \"name\": \"app\",\n  \"version\": \"1.1.0\"\n}\n")
+
+	assert.NilError(t, seer.Path("app/config.server.port").Set(9090).Commit())
+	assert.Equal(t, read("/app/config.toml"), "name = 'app'\n\n[server]\nport = 9090\n")
+
+	assert.NilError(t, seer.Path("app/secrets.TOKEN").Set("xyz").Commit())
+	assert.Equal(t, read("/app/secrets.env"), "TOKEN=xyz\n")
+
+	// files of other formats hold a single document
+	err = seer.Get("app").Get("config").Document().Index(1).Set(map[string]int{"a": 1}).Commit()
+	assert.Assert(t, errors.Is(err, ErrIndexOutOfRange), err)
+	err = seer.Get("app").Get("secrets").Document().Index(1).Set(map[string]int{"a": 1}).Commit()
+	assert.Assert(t, errors.Is(err, ErrIndexOutOfRange), err)
+	assert.NilError(t, seer.Sync())
+
+	_, err = New(VirtualFS(fs, "/"), RegisterCodec(".toml", nil))
+	assert.Assert(t, err != nil)
+}
@@ -0,0 +1,160 @@
+package seer
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type CommentKind int
+
+const (
+	HeadComment CommentKind = iota // lines above the value
+	LineComment                    // end of the line of the value
+	FootComment                    // lines below the value
+)
+
+type comment struct {
+	kind CommentKind
+	text string
+}
+
+// Comments returns the comments of the value, without their `#` markers. For a mapping entry,
+// they are the comments of the entry as a whole, and for a document, the ones of the file.
+func (n *Query) Comments() (head, line, foot string, err error) {
+	unlock, err := n.rlock()
+	if err != nil {
+		return "", "", "", err
+	}
+	defer unlock()
+
+	if n.tx != nil && n.tx.done {
+		return "", "", "", errTxDone
+	}
+
+	n.write = false
+	if len(n.errors) > 0 {
+		return "", "", "", fmt.Errorf("%d errors preventing getting comments", len(n.errors))
+	}
+
+	_, value, err := n.run(n.ops)
+	if err != nil {
+		return "", "", "", &PathError{Op: "comments", Path: n.String(), Err: err}
+	}
+
+	if value == nil || value.this == nil {
+		return "", "", "", fmt.Errorf("only values and documents have comments: %w", ErrNotDocument)
+	}
+
+	key, node := value.prev, value.this
+	if key == nil {
+		return uncomment(node.HeadComment), uncomment(node.LineComment), uncomment(node.FootComment), nil
+	}
+
+	return uncomment(key.HeadComment), uncomment(first(node.LineComment, key.LineComment)), uncomment(first(key.FootComment, node.FootComment)), nil
+}
+
+// SetComment sets the comment of kind of the value, see Comments. An empty text removes it.
+func (n *Query) SetComment(kind CommentKind, text string) *Query {
+	if kind < HeadComment || kind > FootComment {
+		n.errors = append(n.errors, fmt.Errorf("unknown comment kind %d", kind))
+		return n
+	}
+
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			value:   comment{kind: kind, text: text},
+			handler: opSetComment,
+		},
+	)
+	return n
+}
+
+func opSetComment(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to call SetComment(): %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.this == nil {
+		return path, nil, fmt.Errorf("failed to call SetComment(): %w", ErrNotDocument)
+	}
+
+	query.seer.modified(value.document)
+
+	c := this.value.(comment)
+	text := recomment(c.text)
+	key, node := value.prev, value.this
+
+	if key == nil {
+		if node.Kind == 0 {
+			// an empty document
+			*node = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+		}
+
+		switch c.kind {
+		case HeadComment:
+			node.HeadComment = text
+		case LineComment:
+			node.LineComment = text
+		case FootComment:
+			node.FootComment = text
+		}
+		return path, value, nil
+	}
+
+	switch c.kind {
+	case HeadComment:
+		key.HeadComment = text
+	case LineComment:
+		// yaml puts the line comment of a collection after its key
+		if node.Kind == yaml.ScalarNode || node.Kind == yaml.AliasNode {
+			node.LineComment, key.LineComment = text, ""
+		} else {
+			key.LineComment, node.LineComment = text, ""
+		}
+	case FootComment:
+		key.FootComment, node.FootComment = text, ""
+	}
+
+	return path, value, nil
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// uncomment strips the `#` markers from the lines of a comment.
+func uncomment(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		l = strings.TrimPrefix(l, "#")
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recomment adds the `#` markers to the lines of text missing them.
+func recomment(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		if !strings.HasPrefix(l, "#") {
+			lines[i] = strings.TrimRight("# "+l, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
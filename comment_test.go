@@ -0,0 +1,47 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestComments(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/car.yaml", []byte("# generated\n\n# capacity\nbattery: 100 # kWh\nspec: # details\n  seats: 4\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	head, line, foot, err := seer.Get("car").Get("battery").Comments()
+	assert.NilError(t, err)
+	assert.Equal(t, head, "capacity")
+	assert.Equal(t, line, "kWh")
+	assert.Equal(t, foot, "")
+
+	_, line, _, err = seer.Get("car").Get("spec").Comments()
+	assert.NilError(t, err)
+	assert.Equal(t, line, "details")
+
+	head, _, _, err = seer.Get("car").Comments()
+	assert.NilError(t, err)
+	assert.Equal(t, head, "generated")
+
+	assert.NilError(t, seer.Get("car").SetComment(HeadComment, "generated by seer\ndo not edit").Commit())
+	assert.NilError(t, seer.Get("car").Get("battery").SetComment(LineComment, "MWh").SetComment(HeadComment, "").Commit())
+	assert.NilError(t, seer.Get("car").Get("spec").SetComment(LineComment, "").Get("seats").SetComment(LineComment, "# max").Commit())
+	assert.NilError(t, seer.Get("bike").Document().SetComment(HeadComment, "empty").Commit())
+	assert.NilError(t, seer.Sync())
+
+	data, err := afero.ReadFile(fs, "/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "# generated by seer\n# do not edit\n\nbattery: 100 # MWh\nspec:\n    seats: 4 # max\n")
+
+	data, err = afero.ReadFile(fs, "/bike.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "# empty\n\n{}\n")
+
+	_, _, _, err = seer.Get("missing").Comments()
+	assert.Assert(t, err != nil)
+}
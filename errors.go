@@ -0,0 +1,49 @@
+package seer
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound is wrapped by the errors reporting a folder, document or value that does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrNotDocument is wrapped by the errors reporting a value operation outside a document.
+	ErrNotDocument = errors.New("not a document")
+
+	// ErrUnsupportedFile is wrapped by the errors reporting a file seer can not map, like a directory named `*.yaml`.
+	ErrUnsupportedFile = errors.New("unsupported file")
+
+	// ErrIndexOutOfRange is wrapped by the errors reporting a sequence index past its length.
+	ErrIndexOutOfRange = errors.New("index out of range")
+
+	// ErrNotSequence is wrapped by the errors reporting a sequence operation on another kind of value.
+	ErrNotSequence = errors.New("not a sequence")
+
+	// ErrReadOnlyQuery is wrapped by the errors reporting a write operation evaluated during a read.
+	ErrReadOnlyQuery = errors.New("read-only query")
+
+	// ErrReadOnly is wrapped by the errors reporting a write to a Seer opened with ReadOnly().
+	ErrReadOnly = errors.New("read-only seer")
+
+	// ErrDuplicateDocument is wrapped by the errors reporting a document stored in several files, like `x.yaml` and `x.yml`.
+	ErrDuplicateDocument = errors.New("document stored with several extensions")
+
+	// ErrConflict is wrapped by the errors reporting a document whose file was changed by someone else.
+	ErrConflict = errors.New("changed on disk")
+)
+
+// PathError records the operation and the seer path of a failed query.
+type PathError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
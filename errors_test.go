@@ -0,0 +1,48 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("name: tau\nlist: [1, 2]\n"), 0640)
+	fs.MkdirAll("/cars/bad.yaml", 0750)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	var value interface{}
+
+	err = seer.Path("cars/car.missing").Value(&value)
+	assert.Assert(t, errors.Is(err, ErrNotFound), err)
+
+	var perr *PathError
+	assert.Assert(t, errors.As(err, &perr))
+	assert.Equal(t, perr.Op, "value")
+	assert.Equal(t, perr.Path, "cars/car.missing")
+
+	err = seer.Path("cars/other.name").Value(&value)
+	assert.Assert(t, errors.Is(err, ErrNotFound), err)
+
+	err = seer.Path("cars/car.list[5]").Value(&value)
+	assert.Assert(t, errors.Is(err, ErrIndexOutOfRange), err)
+
+	err = seer.Path("cars/car.list").RemoveAt(5).Commit()
+	assert.Assert(t, errors.Is(err, ErrIndexOutOfRange), err)
+
+	err = seer.Path("cars/bad").Value(&value)
+	assert.Assert(t, errors.Is(err, ErrUnsupportedFile), err)
+
+	err = seer.Path("cars").Set("tau").Commit()
+	assert.Assert(t, errors.Is(err, ErrNotDocument), err)
+	assert.Assert(t, errors.As(err, &perr))
+	assert.Equal(t, perr.Op, "commit")
+
+	err = seer.Path("cars/car.name").Set("tau").Value(&value)
+	assert.Assert(t, errors.Is(err, ErrReadOnlyQuery), err)
+}
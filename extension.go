@@ -0,0 +1,72 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultExtensions are the extensions of the document files unless set with Extensions().
+var defaultExtensions = []string{".yaml"}
+
+func validExtension(ext string) error {
+	if len(ext) < 2 || ext[0] != '.' || strings.ContainsAny(ext[1:], "./#") {
+		return fmt.Errorf("invalid extension `%s`", ext)
+	}
+	return nil
+}
+
+// documentExtension returns the extension of the document file name, or "" if it is not one.
+func (s *Seer) documentExtension(name string) string {
+	for _, ext := range s.extensions {
+		if strings.HasSuffix(name, ext) && len(name) > len(ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+func (s *Seer) isDocumentFile(name string) bool {
+	return s.documentExtension(name) != "" && !strings.HasPrefix(name, ".")
+}
+
+// cachedDocumentFile returns the file and the first document of the cached document at path,
+// given without extension.
+func (s *Seer) cachedDocumentFile(path string) (string, *yaml.Node, bool) {
+	for _, ext := range s.extensions {
+		if doc, cached := s.cachedDocument(path + ext); cached {
+			return path + ext, doc, true
+		}
+	}
+	return "", nil, false
+}
+
+// findDocumentFile returns the file of the document at path, given without extension, or "" if
+// there is none. A document stored with several extensions fails with ErrDuplicateDocument.
+func (s *Seer) findDocumentFile(path string) (string, error) {
+	found := make([]string, 0, 1)
+	for _, ext := range s.extensions {
+		st, err := s.fs.Stat(path + ext)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("fetching %s failed with %w", path+ext, err)
+		}
+
+		if st.IsDir() {
+			return "", fmt.Errorf("directory `%s`: %w", path+ext, ErrUnsupportedFile)
+		}
+		found = append(found, path+ext)
+	}
+
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("%s: %w", strings.Join(found, ", "), ErrDuplicateDocument)
+}
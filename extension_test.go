@@ -0,0 +1,68 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestExtensions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/a.yml", []byte("name: a\n"), 0640)
+	afero.WriteFile(fs, "/cars/b.json", []byte(`{"name": "b", "tags": ["x"], "size": 1.5}`), 0640)
+	afero.WriteFile(fs, "/cars/c.yaml", []byte("name: c\n"), 0640)
+	afero.WriteFile(fs, "/cars/d.yaml", []byte("name: d\n"), 0640)
+	afero.WriteFile(fs, "/cars/d.yml", []byte("name: d\n"), 0640)
+	afero.WriteFile(fs, "/cars/e.txt", []byte("e"), 0640)
+	afero.WriteFile(fs, "/cars/.hidden.yaml", []byte("name: hidden\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"), Extensions(".yaml", ".yml", ".json"))
+	assert.NilError(t, err)
+
+	read := func(path string) string {
+		assert.NilError(t, seer.Sync())
+		data, err := afero.ReadFile(fs, path)
+		assert.NilError(t, err)
+		return string(data)
+	}
+
+	for path, expected := range map[string]string{"cars/a.name": "a", "cars/b.name": "b", "cars/c.name": "c"} {
+		var name string
+		assert.NilError(t, seer.Path(path).Value(&name), path)
+		assert.Equal(t, name, expected, path)
+	}
+
+	cars, err := seer.Get("cars").List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cars, []string{"a", "b", "c", "d"})
+
+	// a document stored twice is a conflict
+	var name string
+	err = seer.Path("cars/d.name").Value(&name)
+	assert.Assert(t, errors.Is(err, ErrDuplicateDocument), err)
+
+	err = seer.Path("cars/d.name").Set("d2").Commit()
+	assert.Assert(t, errors.Is(err, ErrDuplicateDocument), err)
+
+	// documents keep their extension
+	assert.NilError(t, seer.Path("cars/a.name").Set("a2").Commit())
+	assert.Equal(t, read("/cars/a.yml"), "name: a2\n")
+
+	assert.NilError(t, seer.Path("cars/b.name").Set("b2").Commit())
+	assert.Equal(t, read("/cars/b.json"), "{\n  \"name\": \"b2\",\n  \"tags\": [\n    \"x\"\n  ],\n  \"size\": 1.5\n}\n")
+
+	assert.NilError(t, seer.Get("cars").Get("a").MoveTo(seer.Get("bikes").Get("a")))
+	_, err = fs.Stat("/bikes/a.yml")
+	assert.NilError(t, err)
+
+	// new documents get the first extension
+	assert.NilError(t, seer.Path("cars/f.name").Set("f").Commit())
+	assert.Equal(t, read("/cars/f.yaml"), "name: f\n")
+
+	for _, extensions := range [][]string{{}, {"yaml"}, {".yaml", ".yaml"}, {".tar.gz"}} {
+		_, err = New(VirtualFS(fs, "/"), Extensions(extensions...))
+		assert.Assert(t, err != nil, extensions)
+	}
+}
@@ -0,0 +1,117 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"time"
+)
+
+// fileLockName is the name of the lock file FileLock() creates at the root of the Seer.
+const fileLockName = ".seer.lock"
+
+// fileLock is an advisory lock on a file shared by the processes opening the same folder.
+// The file holds a token replaced by every writer changing the file system, telling the
+// others their cache is stale.
+type fileLock struct {
+	path       string
+	readOnly   bool
+	generation string   // last token seen, guarded by Seer.cache
+	file       *os.File // the lock file while held exclusively
+}
+
+// lockFile takes the file lock, if any, then releases it along with release. Documents changed
+// by another process since the lock was last taken are dropped from the cache, unless they
+// have changes not synced yet, so they are read again from the file system.
+func (s *Seer) lockFile(write bool, release func()) (unlock func(), err error) {
+	l := s.fileLock
+	if l == nil {
+		return release, nil
+	}
+
+	flag := os.O_RDWR | os.O_CREATE
+	if l.readOnly {
+		flag = os.O_RDONLY
+	}
+
+	f, err := os.OpenFile(l.path, flag, 0640)
+	if err != nil {
+		release()
+		if l.readOnly && errors.Is(err, fs.ErrNotExist) {
+			// no process ever wrote to the folder with a file lock
+			return func() {}, nil
+		}
+		return nil, fmt.Errorf("opening lock file %s failed with %w", l.path, err)
+	}
+
+	unlock = func() {
+		if write {
+			l.file = nil
+		}
+		// closing the file releases the lock
+		f.Close()
+		release()
+	}
+
+	if err = flock(f, write); err != nil {
+		unlock()
+		return nil, fmt.Errorf("locking %s failed with %w", l.path, err)
+	}
+
+	generation, err := io.ReadAll(f)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("reading lock file %s failed with %w", l.path, err)
+	}
+	s.reload(string(generation))
+
+	if write && !l.readOnly {
+		l.file = f
+	}
+
+	return unlock, nil
+}
+
+// reload drops the clean cached documents if generation is not the last token seen.
+func (s *Seer) reload(generation string) {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	if generation == s.fileLock.generation {
+		return
+	}
+	s.fileLock.generation = generation
+
+	for path := range s.documents {
+		if !s.hasDirtyDocuments(documentFile(path)) {
+			delete(s.documents, path)
+		}
+	}
+}
+
+// renewGeneration replaces the token of the lock file, if held exclusively, once the file system
+// was changed. No process can read it before the lock is released.
+func (s *Seer) renewGeneration() error {
+	l := s.fileLock
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	generation := strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	if err := l.file.Truncate(0); err != nil {
+		return fmt.Errorf("writing lock file %s failed with %w", l.path, err)
+	}
+	if _, err := l.file.WriteAt([]byte(generation), 0); err != nil {
+		return fmt.Errorf("writing lock file %s failed with %w", l.path, err)
+	}
+
+	s.cache.Lock()
+	s.fileLock.generation = generation
+	s.cache.Unlock()
+
+	return nil
+}
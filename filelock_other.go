@@ -0,0 +1,14 @@
+//go:build !unix
+
+package seer
+
+import (
+	"errors"
+	"os"
+)
+
+const flockSupported = false
+
+func flock(f *os.File, write bool) error {
+	return errors.New("file locking is not supported on this platform")
+}
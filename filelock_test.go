@@ -0,0 +1,78 @@
+//go:build unix
+
+package seer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestFileLock(t *testing.T) {
+	dir := t.TempDir()
+
+	s1, err := New(SystemFS(dir), FileLock())
+	assert.NilError(t, err)
+
+	s2, err := New(SystemFS(dir), FileLock())
+	assert.NilError(t, err)
+
+	assert.NilError(t, s1.Path("cars/car.name").Set("a").Commit())
+	assert.NilError(t, s1.Sync())
+
+	var name string
+	assert.NilError(t, s2.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "a")
+
+	// the token only changes once the file system is written
+	token, err := os.ReadFile(filepath.Join(dir, fileLockName))
+	assert.NilError(t, err)
+
+	assert.NilError(t, s1.Path("cars/car.name").Set("b").Commit())
+	renewed, err := os.ReadFile(filepath.Join(dir, fileLockName))
+	assert.NilError(t, err)
+	assert.Equal(t, string(renewed), string(token))
+
+	// s2 sees the changes s1 synced since
+	assert.NilError(t, s1.Sync())
+	renewed, err = os.ReadFile(filepath.Join(dir, fileLockName))
+	assert.NilError(t, err)
+	assert.Assert(t, string(renewed) != string(token))
+
+	assert.NilError(t, s2.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "b")
+
+	// unless it changed the document too
+	assert.NilError(t, s2.Path("cars/car.color").Set("red").Commit())
+	assert.NilError(t, s1.Path("cars/car.name").Set("c").Commit())
+	assert.NilError(t, s1.Sync())
+
+	assert.NilError(t, s2.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "b")
+
+	// a writer blocks the readers of other processes
+	unlock, err := s1.lockAll()
+	assert.NilError(t, err)
+
+	done := make(chan error)
+	go func() {
+		var name string
+		done <- s2.Path("cars/car.name").Value(&name)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("reading should wait for the writer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	assert.NilError(t, <-done)
+
+	_, err = New(VirtualFS(afero.NewMemMapFs(), "/"), FileLock())
+	assert.ErrorContains(t, err, "SystemFS")
+}
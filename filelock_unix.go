@@ -0,0 +1,25 @@
+//go:build unix
+
+package seer
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+const flockSupported = true
+
+func flock(f *os.File, write bool) error {
+	how := syscall.LOCK_SH
+	if write {
+		how = syscall.LOCK_EX
+	}
+
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package seer
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// fileState identifies the content of a file when a document was read from or written to it.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+func newFileState(st fs.FileInfo, data []byte) fileState {
+	return fileState{
+		modTime: st.ModTime(),
+		size:    st.Size(),
+		hash:    sha256.Sum256(data),
+	}
+}
+
+// changedOnDisk tells if the file of the document at path changed since the document was read
+// or written. The content is only hashed when the modification time or the size differ.
+func (s *Seer) changedOnDisk(path string) (bool, error) {
+	s.cache.Lock()
+	state, known := s.files[path]
+	s.cache.Unlock()
+
+	if !known {
+		return false, nil
+	}
+
+	st, err := s.fs.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if st.ModTime().Equal(state.modTime) && st.Size() == state.size {
+		return false, nil
+	}
+
+	data, err := afero.ReadFile(s.fs, path)
+	if err != nil {
+		return false, err
+	}
+
+	return sha256.Sum256(data) != state.hash, nil
+}
+
+// conflicts returns an error for each document file at paths changed on disk.
+func (s *Seer) conflicts(op string, paths []string) []error {
+	errs := make([]error, 0)
+	for _, path := range paths {
+		changed, err := s.changedOnDisk(path)
+		if err != nil {
+			errs = append(errs, &PathError{Op: op, Path: path, Err: err})
+		} else if changed {
+			errs = append(errs, &PathError{Op: op, Path: path, Err: ErrConflict})
+		}
+	}
+	return errs
+}
+
+// Reload reads the document at path again from the file system, dropping its changes not synced
+// yet. The path is the one of the document file, as returned by Dirty.
+func (s *Seer) Reload(path string) error {
+	unlock, err := s.lockAll()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path = "/" + strings.TrimPrefix(path, "/")
+
+	docs, state, err := s.readDocumentFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.uncacheDocument(path)
+		return &PathError{Op: "reload", Path: path, Err: fmt.Errorf("%w: %w", ErrNotFound, err)}
+	} else if err != nil {
+		return &PathError{Op: "reload", Path: path, Err: err}
+	}
+
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	s.cacheDocuments(path, docs)
+	s.files[path] = state
+	for key := range s.dirty {
+		if documentFile(key) == path {
+			delete(s.dirty, key)
+		}
+	}
+
+	return nil
+}
+
+// Refresh drops the cached documents whose file changed on disk, so they are read again on their
+// next access. Documents with changes not synced yet are kept, and reported as conflicts.
+func (s *Seer) Refresh() error {
+	unlock, err := s.lockAll()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	paths := s.cachedDocuments("/")
+	sort.Strings(paths)
+
+	errs := make([]error, 0)
+	for _, path := range paths {
+		if documentFile(path) != path {
+			// checked along with the first document of the file
+			continue
+		}
+
+		changed, err := s.changedOnDisk(path)
+		if err != nil {
+			errs = append(errs, &PathError{Op: "refresh", Path: path, Err: err})
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		s.cache.Lock()
+		if s.hasDirtyDocuments(path) {
+			errs = append(errs, &PathError{Op: "refresh", Path: path, Err: ErrConflict})
+		} else {
+			s.cacheDocuments(path, nil)
+		}
+		s.cache.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestExternalChanges(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("name: tau\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	var name string
+	assert.NilError(t, seer.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "tau")
+
+	// rewriting the same content is not a change
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("name: tau\n"), 0640)
+	assert.NilError(t, seer.Refresh())
+
+	// a clean document is read again
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("name: other\n"), 0640)
+	assert.NilError(t, seer.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "tau")
+
+	assert.NilError(t, seer.Refresh())
+	assert.NilError(t, seer.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "other")
+
+	// a modified one is a conflict
+	assert.NilError(t, seer.Path("cars/car.color").Set("red").Commit())
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("name: external\n"), 0640)
+
+	err = seer.Refresh()
+	assert.Assert(t, errors.Is(err, ErrConflict), err)
+
+	err = seer.Sync()
+	assert.Assert(t, errors.Is(err, ErrConflict), err)
+
+	var perr *PathError
+	assert.Assert(t, errors.As(err, &perr))
+	assert.Equal(t, perr.Path, "/cars/car.yaml")
+
+	data, err := afero.ReadFile(fs, "/cars/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "name: external\n")
+
+	// until it is reloaded
+	assert.NilError(t, seer.Reload(perr.Path))
+	assert.DeepEqual(t, seer.Dirty(), []string{})
+	assert.NilError(t, seer.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "external")
+
+	assert.NilError(t, seer.Path("cars/car.color").Set("red").Commit())
+	assert.NilError(t, seer.Sync())
+
+	// changes made by Sync are not conflicts
+	assert.NilError(t, seer.Path("cars/car.color").Set("blue").Commit())
+	assert.NilError(t, seer.Sync())
+
+	// deleting the file is a conflict too
+	assert.NilError(t, seer.Path("cars/car.color").Set("green").Commit())
+	fs.Remove("/cars/car.yaml")
+	err = seer.Sync()
+	assert.Assert(t, errors.Is(err, ErrConflict), err)
+
+	err = seer.Reload("cars/car.yaml")
+	assert.Assert(t, errors.Is(err, ErrNotFound), err)
+	assert.NilError(t, seer.Sync())
+}
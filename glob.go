@@ -0,0 +1,375 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	pathUtils "github.com/taubyte/utils/path"
+	"gopkg.in/yaml.v3"
+)
+
+// recursiveGlob is the wildcard segment matching any number of folders, documents or keys.
+const recursiveGlob = "**"
+
+// Glob appends a wildcard segment to the query. The pattern uses the syntax of path.Match
+// and is matched against the names of folders, documents, keys and sequence indexes.
+// The pattern `**` matches any number of levels, including none.
+//
+// A query with wildcards is evaluated with Matches(). Calling Commit() on it applies its
+// operations to every match at once.
+func (n *Query) Glob(pattern string) *Query {
+	if _, err := path.Match(pattern, ""); err != nil {
+		n.errors = append(n.errors, fmt.Errorf("invalid pattern `%s`: %w", pattern, err))
+		return n
+	}
+
+	n.requestedPath = append(n.requestedPath, pattern)
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeGlob,
+			name:    pattern,
+			handler: opGlob,
+		},
+	)
+	return n
+}
+
+func opGlob(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	return path, nil, fmt.Errorf("wildcard `%s` can not be resolved without expanding the query", this.name)
+}
+
+func (n *Query) hasGlob() bool {
+	return globIndex(n.ops) >= 0
+}
+
+func globIndex(ops []op) int {
+	for i, op := range ops {
+		if op.opType == opTypeGlob {
+			return i
+		}
+	}
+	return -1
+}
+
+// Matches evaluates a query with wildcards and returns a query for every existing value it matches.
+func (n *Query) Matches() (*ResultSet, error) {
+	unlock, err := n.rlock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if n.tx != nil && n.tx.done {
+		return nil, errTxDone
+	}
+
+	if len(n.errors) > 0 {
+		return nil, fmt.Errorf("%d errors preventing matching", len(n.errors))
+	}
+
+	return n.glob()
+}
+
+// glob expands the wildcards of the query. Must be called with the lock held.
+func (n *Query) glob() (*ResultSet, error) {
+	r := &ResultSet{
+		seer:    n.seer,
+		tx:      n.tx,
+		queries: make([]*Query, 0),
+	}
+
+	seen := make(map[string]bool)
+	err := n.expand(n.ops, func(ops []op) {
+		q := &Query{
+			seer:   n.seer,
+			ops:    ops,
+			errors: make([]error, 0),
+			tx:     n.tx,
+		}
+		for _, op := range ops {
+			if op.opType == opTypeGetOrCreate || op.opType == opTypeCreateDocument {
+				q.requestedPath = append(q.requestedPath, op.name)
+			}
+		}
+
+		p := q.String()
+		if !seen[p] {
+			seen[p] = true
+			r.queries = append(r.queries, q)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(r.queries, func(i, j int) bool {
+		return compareSegments(segmentNames(r.queries[i].ops), segmentNames(r.queries[j].ops)) < 0
+	})
+
+	return r, nil
+}
+
+// segmentNames returns the names of the folders, documents, keys and indexes ops lead to.
+func segmentNames(ops []op) []string {
+	nav := navigation(ops)
+	names := make([]string, len(nav))
+	for i, op := range nav {
+		names[i] = op.name
+	}
+	return names
+}
+
+// compareSegments orders paths segment by segment, comparing indexes as numbers, so a value
+// comes before the ones under it and `[2]` before `[10]`.
+func compareSegments(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if isIndex(a[i]) && isIndex(b[i]) {
+			x, _ := strconv.Atoi(a[i])
+			y, _ := strconv.Atoi(b[i])
+			if x != y {
+				return x - y
+			}
+		}
+		return strings.Compare(a[i], b[i])
+	}
+	return len(a) - len(b)
+}
+
+// commitOrder returns matches, sorted by compareSegments, in the order to commit them: deeper
+// values and later sequence items first, so changing one does not move the others. Matches
+// under a deleted one are left out, as committing them would create their parents again.
+func commitOrder(matches []*Query) []*Query {
+	ordered := make([]*Query, 0, len(matches))
+	deleted := make([][]string, 0)
+	for _, q := range matches {
+		names := segmentNames(q.ops)
+		if slices.ContainsFunc(deleted, func(parent []string) bool {
+			return len(parent) < len(names) && slices.Equal(parent, names[:len(parent)])
+		}) {
+			continue
+		}
+
+		if slices.ContainsFunc(q.ops, func(op op) bool { return op.opType == opTypeDelete }) {
+			deleted = append(deleted, names)
+		}
+		ordered = append(ordered, q)
+	}
+
+	slices.Reverse(ordered)
+	return ordered
+}
+
+func (n *Query) expand(ops []op, found func([]op)) error {
+	i := globIndex(ops)
+	if i < 0 {
+		if _, _, err := n.reader().run(navigation(ops)); err == nil {
+			found(ops)
+		}
+		return nil
+	}
+
+	children, err := n.children(ops[:i])
+	if err != nil {
+		// nothing to match under a value that does not exist
+		return nil
+	}
+
+	pattern := ops[i].name
+	if pattern == recursiveGlob {
+		if err = n.expand(joinOps(ops[:i], nil, ops[i+1:]), found); err != nil {
+			return err
+		}
+	}
+
+	for _, child := range children {
+		get := op{opType: opTypeGetOrCreate, name: child, handler: opGetOrCreate}
+		if _, v, err := n.reader().run(navigation(joinOps(ops[:i], &get, nil))); err == nil && v != nil && v.parent == nil {
+			// a document: the keys under it are inside it
+			get = op{opType: opTypeCreateDocument, name: child, handler: opCreateDocument}
+		}
+		if pattern == recursiveGlob {
+			err = n.expand(joinOps(ops[:i], &get, ops[i:]), found)
+		} else if ok, _ := path.Match(pattern, child); ok && n.keep(ops[i], joinOps(ops[:i], &get, nil), child) {
+			err = n.expand(joinOps(ops[:i], &get, ops[i+1:]), found)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keep tells if the child reached with ops passes the filter of the wildcard op.
+func (n *Query) keep(wildcard op, ops []op, child string) bool {
+	if wildcard.filter == nil {
+		return true
+	}
+
+	q := &Query{
+		seer:   n.seer,
+		ops:    ops,
+		errors: make([]error, 0),
+		tx:     n.tx,
+		held:   true,
+	}
+	return wildcard.filter(child, q)
+}
+
+// children lists the names of the folders, documents, keys or indexes under the value ops lead to.
+func (n *Query) children(ops []op) ([]string, error) {
+	_path, doc, err := n.reader().run(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc == nil {
+		return n.seer.listFolder("/" + pathUtils.Join(_path))
+	}
+
+	node := doc.this
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+	if node == nil {
+		return nil, errors.New("empty value")
+	}
+	node = resolveAlias(node)
+
+	names := make([]string, 0)
+	switch node.Kind {
+	case yaml.MappingNode:
+		entries := mappingEntries(node)
+		for i := 0; i+1 < len(entries); i += 2 {
+			names = append(names, entries[i].Value)
+		}
+	case yaml.SequenceNode:
+		for i := range node.Content {
+			names = append(names, strconv.Itoa(i))
+		}
+	}
+
+	return names, nil
+}
+
+// reader returns a read-only query sharing the Seer and Tx of n.
+func (n *Query) reader() *Query {
+	return &Query{seer: n.seer, tx: n.tx}
+}
+
+// navigation returns the ops leading to a value, leaving out the ones changing it.
+func navigation(ops []op) []op {
+	nav := make([]op, 0, len(ops))
+	for _, op := range ops {
+		if op.opType == opTypeGetOrCreate || op.opType == opTypeCreateDocument || op.opType == opTypeIndex {
+			nav = append(nav, op)
+		}
+	}
+	return nav
+}
+
+func joinOps(head []op, middle *op, tail []op) []op {
+	ops := make([]op, 0, len(head)+len(tail)+1)
+	ops = append(ops, head...)
+	if middle != nil {
+		ops = append(ops, *middle)
+	}
+	return append(ops, tail...)
+}
+
+// ResultSet holds a query for each value matched by a query with wildcards.
+type ResultSet struct {
+	seer    *Seer
+	tx      *Tx
+	queries []*Query
+}
+
+func (r *ResultSet) Len() int {
+	return len(r.queries)
+}
+
+// Paths returns the path expression of every match.
+func (r *ResultSet) Paths() []string {
+	paths := make([]string, len(r.queries))
+	for i, q := range r.queries {
+		paths[i] = q.String()
+	}
+	return paths
+}
+
+// Names returns the last segment of the path of every match.
+func (r *ResultSet) Names() []string {
+	names := make([]string, len(r.queries))
+	for i, q := range r.queries {
+		if len(q.requestedPath) > 0 {
+			names[i] = q.requestedPath[len(q.requestedPath)-1]
+		}
+	}
+	return names
+}
+
+// Queries returns a copy of the query of every match.
+func (r *ResultSet) Queries() []*Query {
+	queries := make([]*Query, len(r.queries))
+	for i, q := range r.queries {
+		queries[i] = q.Fork()
+	}
+	return queries
+}
+
+func (r *ResultSet) Set(value interface{}) *ResultSet {
+	for _, q := range r.queries {
+		q.Set(value)
+	}
+	return r
+}
+
+func (r *ResultSet) Delete() *ResultSet {
+	for _, q := range r.queries {
+		q.Delete()
+	}
+	return r
+}
+
+// Commit applies the operations of all matches, or none of them.
+func (r *ResultSet) Commit() error {
+	b := r.seer.Batch(commitOrder(r.queries)...)
+	b.tx = r.tx
+	return b.Commit()
+}
+
+// matchPath tells if the slash separated name matches pattern, where each level is a
+// path.Match pattern and `**` matches any number of levels.
+func matchPath(pattern, name string) bool {
+	return _matchPath(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func _matchPath(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == recursiveGlob {
+		for i := 0; i <= len(name); i++ {
+			if _matchPath(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	ok, _ := path.Match(pattern[0], name[0])
+	return ok && _matchPath(pattern[1:], name[1:])
+}
@@ -0,0 +1,84 @@
+package seer
+
+import (
+	"errors"
+	iofs "io/fs"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestGlob(t *testing.T) {
+	seer, err := New(fixtureFS(true, "/"))
+	assert.NilError(t, err)
+
+	for _, car := range []string{"electric/taumobile", "electric/volt", "gas/guzzler"} {
+		assert.NilError(t, seer.Path("cars/"+car+".Battery").Set(100).Commit())
+	}
+	assert.NilError(t, seer.Path("cars/gas/truck.Tank").Set(80).Commit())
+
+	matches, err := seer.Path("cars/*/*.Battery").Matches()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, matches.Paths(), []string{
+		"cars/electric/taumobile.Battery",
+		"cars/electric/volt.Battery",
+		"cars/gas/guzzler.Battery",
+	})
+
+	var battery int
+	assert.NilError(t, matches.Queries()[1].Value(&battery))
+	assert.Equal(t, battery, 100)
+
+	matches, err = seer.Get("cars").Glob("**").Glob("Bat*").Matches()
+	assert.NilError(t, err)
+	assert.Equal(t, matches.Len(), 3)
+
+	assert.NilError(t, seer.Get("cars").Get("electric").Glob("*").Get("Battery").Set(50).Commit())
+	assert.NilError(t, seer.Path("cars/electric/volt.Battery").Value(&battery))
+	assert.Equal(t, battery, 50)
+	assert.NilError(t, seer.Path("cars/gas/guzzler.Battery").Value(&battery))
+	assert.Equal(t, battery, 100)
+
+	matches, err = seer.Path("cars/gas/*").Matches()
+	assert.NilError(t, err)
+	assert.NilError(t, matches.Delete().Commit())
+
+	items, err := seer.Get("cars").Get("gas").List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(items), 0)
+
+	assert.Assert(t, seer.Path("cars/*").Value(&battery) != nil)
+}
+
+func TestGlobCommitOrder(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/doc.yaml", []byte("list: [a, b, c, d, e, f, g, h, i, j, k, l]\nflags: [{on: true}, {on: true}, {on: false}]\n"), 0640)
+	afero.WriteFile(fs, "/cars/a/x.yaml", []byte("name: x\n"), 0640)
+	afero.WriteFile(fs, "/cars/b.yaml", []byte("name: b\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	// indexes sort as numbers
+	matches, err := seer.Path("doc.list.*").Matches()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, matches.Paths()[:3], []string{"doc.list[0]", "doc.list[1]", "doc.list[2]"})
+	assert.Equal(t, matches.Paths()[10], "doc.list[10]")
+
+	// deleting items does not move the ones left to delete
+	assert.NilError(t, seer.Path("doc.list.*").Delete().Commit())
+	var list []string
+	assert.NilError(t, seer.Path("doc.list").Value(&list))
+	assert.Equal(t, len(list), 0)
+
+	assert.NilError(t, seer.Get("doc").Get("flags").Where("on", "==", true).Delete().Commit())
+	var flags []map[string]bool
+	assert.NilError(t, seer.Path("doc.flags").Value(&flags))
+	assert.DeepEqual(t, flags, []map[string]bool{{"on": false}})
+
+	// deleted folders are not created again by the matches under them
+	assert.NilError(t, seer.Path("cars/**").Delete().Commit())
+	_, err = fs.Stat("/cars")
+	assert.Assert(t, errors.Is(err, iofs.ErrNotExist), err)
+}
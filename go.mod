@@ -3,14 +3,18 @@ module github.com/taubyte/go-seer
 go 1.21
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
+	github.com/google/go-cmp v0.5.8
+	github.com/pelletier/go-toml/v2 v2.2.2
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/spf13/afero v1.6.0
 	github.com/taubyte/utils v0.1.1
 	golang.org/x/exp v0.0.0-20230118134722-a68e582fa157
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
+	gopkg.in/yaml.v3 v3.0.1
 	gotest.tools/v3 v3.4.0
 )
 
 require (
-	github.com/google/go-cmp v0.5.8 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 	golang.org/x/text v0.3.3 // indirect
 )
@@ -0,0 +1,161 @@
+package seer
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// ioFs exposes a read-only io/fs.FS, like an embed.FS, as an afero.Fs.
+type ioFs struct {
+	fsys fs.FS
+}
+
+// name converts an afero path to a valid io/fs path.
+func (f ioFs) name(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+func (f ioFs) Open(name string) (afero.File, error) {
+	file, err := f.fsys.Open(f.name(name))
+	if err != nil {
+		return nil, err
+	}
+	return &ioFile{File: file, name: name}, nil
+}
+
+func (f ioFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+		return nil, readOnlyError("open", name)
+	}
+	return f.Open(name)
+}
+
+func (f ioFs) Stat(name string) (os.FileInfo, error) {
+	return fs.Stat(f.fsys, f.name(name))
+}
+
+func (f ioFs) Name() string {
+	return "ioFs"
+}
+
+func (f ioFs) Create(name string) (afero.File, error) {
+	return nil, readOnlyError("create", name)
+}
+
+func (f ioFs) Mkdir(name string, perm os.FileMode) error {
+	return readOnlyError("mkdir", name)
+}
+
+func (f ioFs) MkdirAll(name string, perm os.FileMode) error {
+	return readOnlyError("mkdir", name)
+}
+
+func (f ioFs) Remove(name string) error {
+	return readOnlyError("remove", name)
+}
+
+func (f ioFs) RemoveAll(name string) error {
+	return readOnlyError("remove", name)
+}
+
+func (f ioFs) Rename(oldname, newname string) error {
+	return readOnlyError("rename", oldname)
+}
+
+func (f ioFs) Chmod(name string, mode os.FileMode) error {
+	return readOnlyError("chmod", name)
+}
+
+func (f ioFs) Chown(name string, uid, gid int) error {
+	return readOnlyError("chown", name)
+}
+
+func (f ioFs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return readOnlyError("chtimes", name)
+}
+
+func readOnlyError(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: ErrReadOnly}
+}
+
+// ioFile adapts an fs.File to afero.File. Reading a directory requires the file to implement fs.ReadDirFile.
+type ioFile struct {
+	fs.File
+	name string
+}
+
+func (f *ioFile) Name() string {
+	return f.name
+}
+
+func (f *ioFile) ReadAt(p []byte, off int64) (int, error) {
+	if r, ok := f.File.(io.ReaderAt); ok {
+		return r.ReadAt(p, off)
+	}
+	return 0, &fs.PathError{Op: "readat", Path: f.name, Err: fs.ErrInvalid}
+}
+
+func (f *ioFile) Seek(offset int64, whence int) (int64, error) {
+	if s, ok := f.File.(io.Seeker); ok {
+		return s.Seek(offset, whence)
+	}
+	return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+}
+
+func (f *ioFile) Readdir(count int) ([]os.FileInfo, error) {
+	dir, ok := f.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	entries, err := dir.ReadDir(count)
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, ierr := entry.Info()
+		if ierr != nil {
+			return infos, ierr
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, err
+}
+
+func (f *ioFile) Readdirnames(count int) ([]string, error) {
+	infos, err := f.Readdir(count)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names, err
+}
+
+func (f *ioFile) Write(p []byte) (int, error) {
+	return 0, readOnlyError("write", f.name)
+}
+
+func (f *ioFile) WriteAt(p []byte, off int64) (int, error) {
+	return 0, readOnlyError("write", f.name)
+}
+
+func (f *ioFile) WriteString(s string) (int, error) {
+	return 0, readOnlyError("write", f.name)
+}
+
+func (f *ioFile) Truncate(size int64) error {
+	return readOnlyError("truncate", f.name)
+}
+
+func (f *ioFile) Sync() error {
+	return nil
+}
@@ -0,0 +1,191 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// journal records the changes made by a group of queries so they can be undone.
+// Journals nest: a Batch committed inside a Tx records into a child of the Tx journal.
+type journal struct {
+	parent    *journal
+	documents map[string]*yaml.Node // state of a document before its first change, nil if it was not cached
+	dirty     map[string]struct{}
+	undo      []func() error
+	events    []Event // reported to the watchers once the changes are kept for good
+}
+
+// begin starts recording changes. Must be called with s.lock held, or with s.lock held for
+// reading and s.writing held.
+func (s *Seer) begin() {
+	j := &journal{
+		parent:    s.journal,
+		documents: make(map[string]*yaml.Node),
+	}
+
+	s.cache.Lock()
+	j.dirty = make(map[string]struct{}, len(s.dirty))
+	for k := range s.dirty {
+		j.dirty[k] = struct{}{}
+	}
+	s.cache.Unlock()
+
+	s.journal = j
+}
+
+// end stops recording and keeps all changes, handing them over to the parent journal if any.
+func (s *Seer) end() {
+	j := s.journal
+	if j == nil {
+		return
+	}
+	s.journal = j.parent
+
+	if p := j.parent; p != nil {
+		for path, doc := range j.documents {
+			if _, exists := p.documents[path]; !exists {
+				p.documents[path] = doc
+			}
+		}
+		p.undo = append(p.undo, j.undo...)
+		p.events = append(p.events, j.events...)
+	} else {
+		s.publish(j.events)
+	}
+}
+
+// finish ends the journal, keeping its changes if the documents it touched still validate.
+// Validation only happens when the outermost journal finishes, so the queries of a Batch
+// or a Tx can go through intermediate states.
+func (s *Seer) finish() error {
+	if j := s.journal; j != nil && j.parent == nil {
+		paths := make([]string, 0, len(j.documents))
+		for path := range j.documents {
+			paths = append(paths, path)
+		}
+
+		if errs := s.validateDocuments(paths); len(errs) > 0 {
+			return s.abort(errors.Join(errs...))
+		}
+
+		// every change made to the file system can be undone
+		if len(j.undo) > 0 {
+			if err := s.renewGeneration(); err != nil {
+				return s.abort(err)
+			}
+		}
+	}
+
+	s.end()
+	return nil
+}
+
+// abort rolls back the journal because of err.
+func (s *Seer) abort(err error) error {
+	if rerr := s.rollback(); rerr != nil {
+		return fmt.Errorf("%w (rolling back failed with %s)", err, rerr)
+	}
+	return err
+}
+
+// rollback undoes every change recorded since the last begin.
+func (s *Seer) rollback() error {
+	j := s.journal
+	if j == nil {
+		return nil
+	}
+	s.journal = j.parent
+
+	s.cache.Lock()
+	for path, doc := range j.documents {
+		if doc == nil {
+			delete(s.documents, path)
+		} else {
+			s.documents[path] = doc
+		}
+	}
+	s.dirty = j.dirty
+	s.cache.Unlock()
+
+	var err error
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		if uerr := j.undo[i](); uerr != nil && err == nil {
+			err = uerr
+		}
+	}
+
+	return err
+}
+
+// record saves the state of the document at path before it gets changed.
+func (s *Seer) record(path string) {
+	if s.journal == nil {
+		return
+	}
+
+	if _, exists := s.journal.documents[path]; !exists {
+		doc, _ := s.cachedDocument(path)
+		s.journal.documents[path] = cloneNode(doc)
+	}
+}
+
+// onUndo registers a function reverting a change made to the file system.
+func (s *Seer) onUndo(undo func() error) {
+	if s.journal != nil {
+		s.journal.undo = append(s.journal.undo, undo)
+	}
+}
+
+// backup saves the file or folder at path so it can be restored if the journal is rolled back.
+func (s *Seer) backup(path string) error {
+	if s.journal == nil {
+		return nil
+	}
+
+	type entry struct {
+		path string
+		mode fs.FileMode
+		data []byte
+	}
+
+	entries := make([]entry, 0)
+	err := afero.Walk(s.fs, path, func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		e := entry{path: p, mode: info.Mode()}
+		if !info.IsDir() {
+			if e.data, err = afero.ReadFile(s.fs, p); err != nil {
+				return err
+			}
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("backing up %s failed with %w", path, err)
+	}
+
+	s.onUndo(func() error {
+		for _, e := range entries {
+			var err error
+			if e.mode.IsDir() {
+				err = s.fs.MkdirAll(e.path, e.mode.Perm())
+			} else {
+				err = afero.WriteFile(s.fs, e.path, e.data, e.mode.Perm())
+			}
+			if err != nil && !os.IsExist(err) {
+				return fmt.Errorf("restoring %s failed with %w", e.path, err)
+			}
+		}
+		return nil
+	})
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindFolder
+	KindDocument
+	KindMapping
+	KindSequence
+	KindScalar
+	KindAlias
+	KindNull
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindFolder:
+		return "folder"
+	case KindDocument:
+		return "document"
+	case KindMapping:
+		return "mapping"
+	case KindSequence:
+		return "sequence"
+	case KindScalar:
+		return "scalar"
+	case KindAlias:
+		return "alias"
+	case KindNull:
+		return "null"
+	}
+	return "unknown"
+}
+
+// Exists tells if the folder, document or value of the query exists. It never creates anything.
+func (n *Query) Exists() (bool, error) {
+	_, err := n.Kind()
+	if isMissing(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Kind returns the kind of the folder, document or value of the query. It never creates anything.
+func (n *Query) Kind() (Kind, error) {
+	unlock, err := n.rlock()
+	if err != nil {
+		return KindUnknown, err
+	}
+	defer unlock()
+
+	if n.tx != nil && n.tx.done {
+		return KindUnknown, errTxDone
+	}
+
+	if len(n.errors) > 0 {
+		return KindUnknown, fmt.Errorf("%d errors preventing getting kind", len(n.errors))
+	}
+
+	if n.hasGlob() {
+		return KindUnknown, errors.New("a query with wildcards can only be evaluated with Matches()")
+	}
+
+	_, value, err := n.reader().run(navigation(n.ops))
+	if err != nil {
+		return KindUnknown, &PathError{Op: "kind", Path: n.String(), Err: err}
+	}
+
+	switch {
+	case value == nil:
+		return KindFolder, nil
+	case value.parent == nil:
+		return KindDocument, nil
+	}
+
+	switch value.this.Kind {
+	case yaml.MappingNode:
+		return KindMapping, nil
+	case yaml.SequenceNode:
+		return KindSequence, nil
+	case yaml.AliasNode:
+		return KindAlias, nil
+	case yaml.ScalarNode:
+		if value.this.Tag == "!!null" {
+			return KindNull, nil
+		}
+		return KindScalar, nil
+	}
+
+	return KindUnknown, nil
+}
+
+func isMissing(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrIndexOutOfRange) || errors.Is(err, fs.ErrNotExist)
+}
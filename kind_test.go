@@ -0,0 +1,51 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestKind(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("base: &b {a: 1}\nref: *b\nlist: [1]\nname: tau\nnothing: null\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	for path, expected := range map[string]Kind{
+		"cars":             KindFolder,
+		"cars/car":         KindDocument,
+		"cars/car.base":    KindMapping,
+		"cars/car.ref":     KindAlias,
+		"cars/car.list":    KindSequence,
+		"cars/car.name":    KindScalar,
+		"cars/car.nothing": KindNull,
+	} {
+		kind, err := seer.Path(path).Kind()
+		assert.NilError(t, err, path)
+		assert.Equal(t, kind, expected, path)
+
+		exists, err := seer.Path(path).Exists()
+		assert.NilError(t, err, path)
+		assert.Assert(t, exists, path)
+	}
+
+	for _, path := range []string{"bikes", "bikes/bmx.name", "cars/other", "cars/car.missing", "cars/car.list[3]", "cars/car.list.a"} {
+		exists, err := seer.Path(path).Exists()
+		assert.NilError(t, err, path)
+		assert.Assert(t, !exists, path)
+
+		_, err = seer.Path(path).Kind()
+		assert.Assert(t, err != nil, path)
+	}
+
+	// nothing gets created, even by write-capable paths
+	exists, err := seer.Get("bikes").Get("bmx").Document().Get("name").Exists()
+	assert.NilError(t, err)
+	assert.Assert(t, !exists)
+
+	_, err = fs.Stat("/bikes")
+	assert.Assert(t, err != nil)
+}
@@ -0,0 +1,89 @@
+package seer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestPathLocks(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/a.yaml", []byte("name: a\n"), 0640)
+	afero.WriteFile(fs, "/cars/b.yaml", []byte("name: b\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, seer.Path("cars/a.name").lockedPath(), []string{"cars", "a"})
+	assert.DeepEqual(t, seer.Get("cars").Get("a").Get("name").lockedPath(), []string{"cars", "a"})
+	assert.DeepEqual(t, seer.Path("cars/*.name").lockedPath(), []string{"cars"})
+	assert.DeepEqual(t, seer.Path("bikes/bmx.name").lockedPath(), []string{"bikes", "bmx"})
+
+	// a write to cars/a blocks its readers, but not the readers of cars/b
+	unlock := seer.paths.lock([]string{"cars", "a"}, true)
+
+	var name string
+	assert.NilError(t, seer.Path("cars/b.name").Value(&name))
+	assert.Equal(t, name, "b")
+
+	done := make(chan error)
+	go func() {
+		var name string
+		done <- seer.Path("cars/a.name").Value(&name)
+	}()
+
+	go func() {
+		var names []string
+		done <- seer.Path("cars").Value(&names)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("reading a locked document should block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	assert.NilError(t, <-done)
+	assert.NilError(t, <-done)
+}
+
+func TestConcurrentQueries(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			doc := fmt.Sprintf("cars/car%d", i%4)
+			for j := 0; j < 20; j++ {
+				assert.NilError(t, seer.Path(doc+".count").Set(j).Commit())
+
+				var count int
+				assert.NilError(t, seer.Path(doc+".count").Value(&count))
+
+				_, err := seer.Get("cars").List()
+				assert.NilError(t, err)
+
+				if j%5 == 0 {
+					assert.NilError(t, seer.Sync())
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NilError(t, seer.Sync())
+	list, err := seer.Get("cars").List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(list), 4)
+}
@@ -0,0 +1,138 @@
+package seer
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Merge reconciles value with the existing data instead of replacing it like Set does.
+// Keys that still exist keep their comments, style and position, new keys are appended
+// and keys missing from value are left untouched. Sequences are merged item by item, and
+// keep their items past the length of value.
+func (n *Query) Merge(value interface{}) *Query {
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			value:   value,
+			handler: opMergeInYaml,
+		},
+	)
+	return n
+}
+
+// Reconcile works like Merge, but also drops the keys missing from value, and the sequence
+// items past its length.
+func (n *Query) Reconcile(value interface{}) *Query {
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			value:   value,
+			prune:   true,
+			handler: opMergeInYaml,
+		},
+	)
+	return n
+}
+
+func opMergeInYaml(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to call Merge(): %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.this == nil {
+		return path, nil, fmt.Errorf("failed to call Merge(): %w", ErrNotDocument)
+	}
+
+	src := &yaml.Node{}
+	if err := src.Encode(this.value); err != nil {
+		return path, nil, err
+	}
+
+	query.seer.modified(value.document)
+	for _, dropped := range mergeNode(value.this, src, this.prune) {
+		// the journal restores the document when the commit fails
+		if err := query.seer.checkUnaliased(value.document, dropped, nil); err != nil {
+			return path, nil, err
+		}
+	}
+
+	return path, value, nil
+}
+
+// mergeNode merges src into dst, keeping what can be kept of dst. It returns the nodes of dst
+// it dropped.
+func mergeNode(dst, src *yaml.Node, prune bool) (dropped []*yaml.Node) {
+	if dst.Kind == yaml.DocumentNode {
+		if len(dst.Content) == 0 {
+			dst.Content = []*yaml.Node{src}
+			return nil
+		}
+		return mergeNode(dst.Content[0], src, prune)
+	}
+
+	if dst.Kind != src.Kind {
+		dropped = append(dropped, dst.Content...)
+		replaceNode(dst, src)
+		return dropped
+	}
+
+	switch dst.Kind {
+	case yaml.MappingNode:
+		kept := make(map[string]bool, len(src.Content)/2)
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, val := src.Content[i], src.Content[i+1]
+			kept[key.Value] = true
+			if existing := childNode(dst, key.Value); existing != nil {
+				dropped = append(dropped, mergeNode(existing, val, prune)...)
+			} else {
+				dst.Content = append(dst.Content, key, val)
+			}
+		}
+
+		if prune {
+			content := make([]*yaml.Node, 0, len(dst.Content))
+			for i := 0; i+1 < len(dst.Content); i += 2 {
+				if kept[dst.Content[i].Value] {
+					content = append(content, dst.Content[i], dst.Content[i+1])
+				} else {
+					dropped = append(dropped, dst.Content[i], dst.Content[i+1])
+				}
+			}
+			dst.Content = content
+		}
+	case yaml.SequenceNode:
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				dropped = append(dropped, mergeNode(dst.Content[i], item, prune)...)
+			} else {
+				dst.Content = append(dst.Content, item)
+			}
+		}
+		if prune && len(dst.Content) > len(src.Content) {
+			dropped = append(dropped, dst.Content[len(src.Content):]...)
+			dst.Content = dst.Content[:len(src.Content)]
+		}
+	case yaml.ScalarNode:
+		if dst.Tag != src.Tag {
+			// a quoted style could change the type of the new value
+			dst.Style = src.Style
+		}
+		dst.Tag = src.Tag
+		dst.Value = src.Value
+	default:
+		dropped = append(dropped, dst.Content...)
+		replaceNode(dst, src)
+	}
+	return dropped
+}
+
+// replaceNode makes dst a copy of src, keeping the comments and the anchor of dst.
+func replaceNode(dst, src *yaml.Node) {
+	head, line, foot, anchor := dst.HeadComment, dst.LineComment, dst.FootComment, dst.Anchor
+	*dst = *src
+	dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+	if dst.Kind != yaml.AliasNode {
+		dst.Anchor = anchor
+	}
+}
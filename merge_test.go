@@ -0,0 +1,80 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+const mergeFixture = `# car
+spec:
+  # kWh
+  battery: 100 # max
+  range: 400
+  colors:
+    - red # default
+    - blue
+name: 'taumobile'
+`
+
+func TestMerge(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/car.yaml", []byte(mergeFixture), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	type spec struct {
+		Battery int      `yaml:"battery"`
+		Seats   int      `yaml:"seats"`
+		Colors  []string `yaml:"colors"`
+	}
+
+	err = seer.Get("car").Get("spec").Merge(spec{Battery: 120, Seats: 4, Colors: []string{"green"}}).Commit()
+	assert.NilError(t, err)
+	assert.NilError(t, seer.Get("car").Get("name").Merge(42).Commit())
+	assert.NilError(t, seer.Sync())
+
+	data, err := afero.ReadFile(fs, "/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `# car
+spec:
+    # kWh
+    battery: 120 # max
+    range: 400
+    colors:
+        - green # default
+        - blue
+    seats: 4
+name: 42
+`)
+
+	assert.NilError(t, seer.Get("car").Get("spec").Get("colors").Reconcile([]string{"white"}).Commit())
+	assert.NilError(t, seer.Sync())
+
+	data, err = afero.ReadFile(fs, "/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `# car
+spec:
+    # kWh
+    battery: 120 # max
+    range: 400
+    colors:
+        - white # default
+    seats: 4
+name: 42
+`)
+
+	assert.NilError(t, seer.Get("car").Get("spec").Reconcile(map[string]int{"battery": 90}).Commit())
+	assert.NilError(t, seer.Sync())
+
+	data, err = afero.ReadFile(fs, "/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `# car
+spec:
+    # kWh
+    battery: 90 # max
+name: 42
+`)
+}
@@ -0,0 +1,342 @@
+package seer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/spf13/afero"
+	pathUtils "github.com/taubyte/utils/path"
+	"gopkg.in/yaml.v3"
+)
+
+// CopyTo copies the folder, document or value of the query to dst, with its comments.
+// A value can be copied to a key of any document or to a document of its own, a document
+// to a key or to another document, and a folder to a new folder. Existing values and
+// documents at dst are replaced.
+func (n *Query) CopyTo(dst *Query) error {
+	return n.transfer(dst, false)
+}
+
+// MoveTo works like CopyTo, then deletes the source. Documents and folders moved to a new
+// place in the file system are renamed, keeping their pending changes.
+func (n *Query) MoveTo(dst *Query) error {
+	return n.transfer(dst, true)
+}
+
+func (n *Query) transfer(dst *Query, move bool) error {
+	if dst.seer != n.seer || dst.tx != n.tx {
+		return errors.New("can not transfer between different seers or transactions")
+	}
+
+	unlock, err := n.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if n.tx != nil && n.tx.done {
+		return errTxDone
+	}
+
+	if len(n.errors) > 0 || len(dst.errors) > 0 {
+		return fmt.Errorf("%d errors preventing transfer", len(n.errors)+len(dst.errors))
+	}
+
+	if n.hasGlob() || dst.hasGlob() {
+		return errors.New("can not transfer with wildcards")
+	}
+
+	if n.seer.readOnly {
+		return &PathError{Op: "transfer", Path: n.String(), Err: ErrReadOnly}
+	}
+
+	src, to := n.Fork(), dst.Fork()
+	src.held, to.held = true, true
+	src.ops, to.ops = navigation(src.ops), navigation(to.ops)
+
+	n.seer.begin()
+	if err := n.seer.transfer(src, to, move); err != nil {
+		return n.seer.abort(fmt.Errorf("transferring %s to %s failed with %w", n.String(), dst.String(), err))
+	}
+
+	return n.seer.finish()
+}
+
+type transferredNode struct {
+	node *yaml.Node
+	key  *yaml.Node // key of the node when it comes from a mapping
+}
+
+func (s *Seer) transfer(src, dst *Query, move bool) error {
+	if len(src.ops) == 0 || len(dst.ops) == 0 {
+		return errors.New("the root can not be transferred")
+	}
+
+	srcPath, srcNode, err := src.reader().run(src.ops)
+	if err != nil {
+		return err
+	}
+
+	isFolder := srcNode == nil
+	isDocument := !isFolder && srcNode.parent == nil
+	srcFs := "/" + pathUtils.Join(srcPath)
+
+	var put *transferredNode
+	if !isFolder {
+		put = &transferredNode{node: cloneNode(srcNode.this), key: srcNode.prev}
+	}
+
+	_, dstNode, err := dst.reader().run(dst.ops)
+	if err == nil {
+		// replacing an existing value or document
+		if dstNode == nil {
+			return errors.New("destination folder already exists")
+		}
+		if isFolder {
+			return errors.New("a folder can only be transferred to a new folder")
+		}
+		if srcNode.this == dstNode.this {
+			return nil
+		}
+
+		if err = s.putNode(dst, put); err != nil {
+			return err
+		}
+		return s.deleteSource(src, move)
+	}
+
+	if isPrefix(opNames(src.ops), opNames(dst.ops)) {
+		return errors.New("can not transfer into itself")
+	}
+
+	// create what is missing up to the parent of the destination
+	parentOps, last := dst.ops[:len(dst.ops)-1], dst.ops[len(dst.ops)-1]
+	parentPath, parent, err := s.heldQuery().withOps(parentOps).writer().run(parentOps)
+	if err != nil {
+		return err
+	}
+
+	if parent != nil {
+		// a new key inside a document
+		if isFolder {
+			return fmt.Errorf("a folder can not be transferred into a document: %w", ErrNotDocument)
+		}
+
+		if err = s.putNode(dst, put); err != nil {
+			return err
+		}
+		return s.deleteSource(src, move)
+	}
+
+	dstFs := "/" + pathUtils.Join(append(parentPath, last.name))
+	switch {
+	case isFolder:
+		if move {
+			return s.rename(srcFs, dstFs)
+		}
+		return s.copyFolder(srcFs, dstFs)
+	case isDocument && move && documentFile(srcNode.document) == srcNode.document:
+		return s.rename(srcNode.document, dstFs+s.documentExtension(srcNode.document))
+	default:
+		doc := s.heldQuery().withOps(dst.ops)
+		if last.opType != opTypeCreateDocument {
+			doc.Document()
+		}
+
+		if err = s.putNode(doc, put); err != nil {
+			return err
+		}
+		return s.deleteSource(src, move)
+	}
+}
+
+func opNames(ops []op) []string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = op.name
+	}
+	return names
+}
+
+// withOps replaces the ops of the query.
+func (n *Query) withOps(ops []op) *Query {
+	n.ops = make([]op, len(ops))
+	copy(n.ops, ops)
+	return n
+}
+
+// writer makes the query a write query, as Commit does.
+func (n *Query) writer() *Query {
+	n.write = true
+	return n
+}
+
+func (s *Seer) putNode(dst *Query, put *transferredNode) error {
+	q := dst.Fork()
+	q.ops = append(q.ops, op{opType: opTypeSet, value: put, handler: opPutNode})
+	return q.commit()
+}
+
+func (s *Seer) deleteSource(src *Query, move bool) error {
+	if !move {
+		return nil
+	}
+	return src.Fork().Delete().commit()
+}
+
+func opPutNode(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to put a node: %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.this == nil {
+		return path, nil, fmt.Errorf("failed to put a node: %w", ErrNotDocument)
+	}
+
+	query.seer.modified(value.document)
+
+	put := this.value.(*transferredNode)
+	node := cloneNode(put.node)
+
+	switch {
+	case value.this.Kind == yaml.DocumentNode && node.Kind != yaml.DocumentNode:
+		value.this.Content = []*yaml.Node{node}
+	case value.this.Kind != yaml.DocumentNode && node.Kind == yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+		} else {
+			node = node.Content[0]
+		}
+		*value.this = *node
+	default:
+		*value.this = *node
+	}
+
+	if put.key != nil && value.prev != nil {
+		value.prev.HeadComment = put.key.HeadComment
+		value.prev.LineComment = put.key.LineComment
+		value.prev.FootComment = put.key.FootComment
+	}
+
+	return path, value, nil
+}
+
+// rename moves the file or folder at from to to, along with the cached documents it holds.
+func (s *Seer) rename(from, to string) error {
+	if _, err := s.fs.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+
+	st, err := s.fs.Stat(from)
+	if err != nil {
+		return fmt.Errorf("renaming %s failed with %w", from, err)
+	}
+	deleted := s.deleted(from)
+
+	if st.IsDir() {
+		// some file systems, like afero's MemMapFs, leave the content behind when renaming a folder
+		if err = s.copyFiles(from, to); err == nil {
+			if err = s.backup(from); err == nil {
+				err = s.fs.RemoveAll(from)
+			}
+		}
+	} else if err = s.fs.Rename(from, to); err == nil {
+		s.onUndo(func() error { return s.fs.Rename(to, from) })
+	}
+	if err != nil {
+		return fmt.Errorf("renaming %s failed with %w", from, err)
+	}
+	s.notify(deleted...)
+	s.notify(s.created(to)...)
+
+	moved := make([]string, 0)
+	for _, path := range s.cachedDocuments(from) {
+		if path == from || strings.HasPrefix(path, from+"/") || strings.HasPrefix(path, from+"#") {
+			s.record(path)
+			s.record(to + strings.TrimPrefix(path, from))
+			moved = append(moved, path)
+		}
+	}
+
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	for _, path := range moved {
+		newPath := to + strings.TrimPrefix(path, from)
+		s.documents[newPath] = s.documents[path]
+		delete(s.documents, path)
+		if state, known := s.files[path]; known {
+			s.files[newPath] = state
+		}
+		if _, dirty := s.dirty[path]; dirty {
+			delete(s.dirty, path)
+			s.dirty[newPath] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// copyFolder copies the folder at from to to, along with the pending changes of its cached documents.
+func (s *Seer) copyFolder(from, to string) error {
+	if err := s.copyFiles(from, to); err != nil {
+		return fmt.Errorf("copying %s failed with %w", from, err)
+	}
+	s.notify(s.created(to)...)
+
+	files := make([]string, 0)
+	keys := make([]string, 0)
+	for _, file := range s.dirtyFiles() {
+		if strings.HasPrefix(file, from+"/") {
+			// the file is written back with all of its documents, dirty or not
+			files = append(files, file)
+			keys = append(keys, s.documentKeys(file)...)
+		}
+	}
+
+	for _, key := range keys {
+		s.record(to + strings.TrimPrefix(key, from))
+	}
+
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	for _, key := range keys {
+		newKey := to + strings.TrimPrefix(key, from)
+		s.documents[newKey] = cloneNode(s.documents[key])
+		if _, dirty := s.dirty[key]; dirty {
+			s.dirty[newKey] = struct{}{}
+		}
+	}
+	for _, file := range files {
+		if state, known := s.files[file]; known {
+			s.files[to+strings.TrimPrefix(file, from)] = state
+		}
+	}
+
+	return nil
+}
+
+// copyFiles copies the files of the folder at from to to.
+func (s *Seer) copyFiles(from, to string) error {
+	s.onUndo(func() error { return s.fs.RemoveAll(to) })
+
+	return afero.Walk(s.fs, from, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := to + strings.TrimPrefix(path, from)
+		if info.IsDir() {
+			return s.fs.MkdirAll(target, info.Mode().Perm())
+		}
+
+		data, err := afero.ReadFile(s.fs, path)
+		if err != nil {
+			return err
+		}
+		return afero.WriteFile(s.fs, target, data, info.Mode().Perm())
+	})
+}
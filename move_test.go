@@ -0,0 +1,73 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestMoveAndCopy(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/services/api.yaml", []byte("# api\nport: 443 # https\n# limits\nlimits:\n  cpu: 2 # cores\n"), 0640)
+	afero.WriteFile(fs, "/services/db.yaml", []byte("port: 5432\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	read := func(path string) string {
+		assert.NilError(t, seer.Sync())
+		data, err := afero.ReadFile(fs, path)
+		assert.NilError(t, err)
+		return string(data)
+	}
+
+	// between two keys of a document
+	assert.NilError(t, seer.Path("services/api.limits").MoveTo(seer.Path("services/api.quota")))
+	assert.Equal(t, read("/services/api.yaml"), "# api\nport: 443 # https\n# limits\nquota:\n    cpu: 2 # cores\n")
+
+	// between documents
+	assert.NilError(t, seer.Path("services/api.quota").CopyTo(seer.Path("services/db.quota")))
+	assert.Equal(t, read("/services/db.yaml"), "port: 5432\n# limits\nquota:\n    cpu: 2 # cores\n")
+
+	// from a key to its own document
+	assert.NilError(t, seer.Path("services/db.quota").MoveTo(seer.Path("quotas/db")))
+	assert.Equal(t, read("/quotas/db.yaml"), "cpu: 2 # cores\n")
+	assert.Equal(t, read("/services/db.yaml"), "port: 5432\n")
+
+	// renaming a document keeps its pending changes
+	assert.NilError(t, seer.Path("services/db.port").Set(5433).Commit())
+	assert.NilError(t, seer.Path("services/db").MoveTo(seer.Path("services/postgres")))
+	assert.DeepEqual(t, seer.Dirty(), []string{"/services/postgres.yaml"})
+	assert.Equal(t, read("/services/postgres.yaml"), "port: 5433\n")
+	_, err = fs.Stat("/services/db.yaml")
+	assert.Assert(t, err != nil)
+
+	// whole folders
+	assert.NilError(t, seer.Path("services/api.port").Set(8443).Commit())
+	assert.NilError(t, seer.Path("services").CopyTo(seer.Path("backup/services")))
+	assert.NilError(t, seer.Path("services").MoveTo(seer.Path("apps")))
+
+	var port int
+	assert.NilError(t, seer.Path("apps/api.port").Value(&port))
+	assert.Equal(t, port, 8443)
+	assert.NilError(t, seer.Path("backup/services/api.port").Value(&port))
+	assert.Equal(t, port, 8443)
+	assert.Equal(t, read("/backup/services/postgres.yaml"), "port: 5433\n")
+
+	items, err := seer.List()
+	assert.NilError(t, err)
+	assertContains(t, items, "apps", "backup", "quotas")
+	assert.Equal(t, len(items), 3)
+
+	assert.Assert(t, seer.Path("apps").MoveTo(seer.Path("apps/inner")) != nil)
+	assert.Assert(t, seer.Path("apps").MoveTo(seer.Path("backup")) != nil)
+	assert.Assert(t, seer.Path("apps/missing").CopyTo(seer.Path("quotas/missing")) != nil)
+
+	// files holding many documents are copied whole, even when only some of them changed
+	afero.WriteFile(fs, "/manifests/app.yaml", []byte("kind: Deployment\n---\nkind: Service\n"), 0640)
+	assert.NilError(t, seer.Path("manifests/app#1.kind").Set("Ingress").Commit())
+	assert.NilError(t, seer.Path("manifests").CopyTo(seer.Path("copy")))
+	assert.Equal(t, read("/copy/app.yaml"), "kind: Deployment\n---\nkind: Ingress\n")
+	assert.Equal(t, read("/manifests/app.yaml"), "kind: Deployment\n---\nkind: Ingress\n")
+}
@@ -0,0 +1,180 @@
+package seer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The documents of a file after the first one are cached under the path of the file followed
+// by `#` and their index, so each of them can be recorded, changed and validated on its own.
+
+// documentKey returns the key of the document at index in the file at path.
+func documentKey(path string, index int) string {
+	if index == 0 {
+		return path
+	}
+	return path + "#" + strconv.Itoa(index)
+}
+
+// documentFile returns the path of the file holding the document cached under key.
+func documentFile(key string) string {
+	file, _ := splitDocumentKey(key)
+	return file
+}
+
+func splitDocumentKey(key string) (string, int) {
+	i := strings.LastIndex(key, "#")
+	if i < 0 {
+		return key, 0
+	}
+
+	index, err := strconv.Atoi(key[i+1:])
+	if err != nil || index <= 0 {
+		return key, 0
+	}
+
+	return key[:i], index
+}
+
+// fileDocuments returns the cached documents of the file at path, in order.
+func (s *Seer) fileDocuments(path string) []*yaml.Node {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	docs := make([]*yaml.Node, 0, 1)
+	for {
+		doc, cached := s.documents[documentKey(path, len(docs))]
+		if !cached {
+			return docs
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// documentKeys returns the keys of the cached documents of the file at path.
+func (s *Seer) documentKeys(path string) []string {
+	docs := s.fileDocuments(path)
+	keys := make([]string, len(docs))
+	for i := range docs {
+		keys[i] = documentKey(path, i)
+	}
+	return keys
+}
+
+// cacheDocuments replaces the cached documents of the file at path with docs. Must be called
+// with s.cache held.
+func (s *Seer) cacheDocuments(path string, docs []*yaml.Node) {
+	for i := len(docs); ; i++ {
+		key := documentKey(path, i)
+		if _, cached := s.documents[key]; !cached {
+			break
+		}
+		delete(s.documents, key)
+		delete(s.dirty, key)
+	}
+
+	for i, doc := range docs {
+		s.documents[documentKey(path, i)] = doc
+	}
+}
+
+// dirtyFiles returns the paths of the files holding documents modified since the last Sync.
+func (s *Seer) dirtyFiles() []string {
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, key := range s.dirtyDocuments() {
+		if file := documentFile(key); !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// hasDirtyDocuments tells if a document of the file at path has changes not synced yet. Must be
+// called with s.cache held.
+func (s *Seer) hasDirtyDocuments(path string) bool {
+	for key := range s.dirty {
+		if documentFile(key) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Index selects the document at index in a file holding many, separated by `---`. Documents are
+// counted from 0, which is the one Document() selects. Committing a query on the index following
+// the last document appends a new one, and deleting a document selected with Index removes it
+// from the file instead of deleting the file.
+func (n *Query) Index(index int) *Query {
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeIndex,
+			name:    strconv.Itoa(index),
+			value:   index,
+			handler: opIndexDocument,
+		},
+	)
+	return n
+}
+
+func opIndexDocument(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if value == nil || value.parent != nil || documentFile(value.document) != value.document {
+		return path, nil, fmt.Errorf("failed to call Index() outside a document: %w", ErrNotDocument)
+	}
+
+	index := this.value.(int)
+	key := documentKey(value.document, index)
+	if doc, cached := query.seer.cachedDocument(key); cached {
+		return path, &yamlNode{this: doc, document: key, indexed: true}, nil
+	}
+
+	count := len(query.seer.fileDocuments(value.document))
+	if !query.write || index != count {
+		return path, nil, fmt.Errorf("document %d (Count: %d): %w", index, count, ErrIndexOutOfRange)
+	}
+
+	if codec, ok := query.seer.codecOf(value.document).(MultiDocumentCodec); !ok || !codec.MultiDocument() {
+		return path, nil, fmt.Errorf("document %d of %s, which holds a single document: %w", index, value.document, ErrIndexOutOfRange)
+	}
+
+	// append an empty document
+	doc := &yaml.Node{}
+	query.seer.record(key)
+	query.seer.cache.Lock()
+	query.seer.documents[key] = doc
+	query.seer.cache.Unlock()
+	query.seer.modified(key)
+
+	return path, &yamlNode{this: doc, document: key, indexed: true}, nil
+}
+
+// removeDocument removes the document cached under key from its file, the following ones
+// moving up by one. Removing the only document of a file leaves it empty.
+func (s *Seer) removeDocument(key string) {
+	file, index := splitDocumentKey(key)
+	docs := s.fileDocuments(file)
+
+	for i := index; i < len(docs); i++ {
+		s.modified(documentKey(file, i))
+	}
+
+	docs = append(docs[:index:index], docs[index+1:]...)
+	if len(docs) == 0 {
+		docs = append(docs, &yaml.Node{})
+	}
+
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	s.cacheDocuments(file, docs)
+	for i := index; i < len(docs); i++ {
+		s.dirty[documentKey(file, i)] = struct{}{}
+	}
+	s.dirty[file] = struct{}{}
+}
@@ -0,0 +1,77 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestMultiDocument(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/manifests.yaml", []byte("kind: A\n---\nkind: B\n---\n# c\nkind: C\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	read := func() string {
+		assert.NilError(t, seer.Sync())
+		data, err := afero.ReadFile(fs, "/manifests.yaml")
+		assert.NilError(t, err)
+		return string(data)
+	}
+
+	for index, expected := range []string{"A", "B", "C"} {
+		var kind string
+		assert.NilError(t, seer.Get("manifests").Document().Index(index).Get("kind").Value(&kind))
+		assert.Equal(t, kind, expected)
+	}
+
+	var kind string
+	assert.NilError(t, seer.Path("manifests.kind").Value(&kind))
+	assert.Equal(t, kind, "A")
+
+	err = seer.Get("manifests").Document().Index(3).Get("kind").Value(&kind)
+	assert.Assert(t, errors.Is(err, ErrIndexOutOfRange), err)
+
+	// every document is written back
+	assert.NilError(t, seer.Get("manifests").Document().Index(1).Get("kind").Set("B2").Commit())
+	assert.DeepEqual(t, seer.Dirty(), []string{"/manifests.yaml"})
+	assert.Equal(t, read(), "kind: A\n---\nkind: B2\n---\n# c\nkind: C\n")
+
+	// appending
+	assert.NilError(t, seer.Get("manifests").Document().Index(3).Get("kind").Set("D").Commit())
+	assert.Equal(t, read(), "kind: A\n---\nkind: B2\n---\n# c\nkind: C\n---\nkind: D\n")
+
+	err = seer.Get("manifests").Document().Index(5).Get("kind").Set("F").Commit()
+	assert.Assert(t, errors.Is(err, ErrIndexOutOfRange), err)
+
+	// removing
+	assert.NilError(t, seer.Get("manifests").Document().Index(1).Delete().Commit())
+	assert.Equal(t, read(), "kind: A\n---\n# c\nkind: C\n---\nkind: D\n")
+
+	// removing the first one
+	assert.NilError(t, seer.Get("manifests").Document().Index(0).Delete().Commit())
+	assert.Equal(t, read(), "# c\nkind: C\n---\nkind: D\n")
+	assert.NilError(t, seer.Get("manifests").Document().Index(0).Get("kind").Set("C").Commit())
+
+	// rolling back
+	err = seer.Batch(
+		seer.Get("manifests").Document().Index(0).Delete(),
+		seer.Get("manifests").Document().Index(1).Get("kind").Set("E"),
+		seer.Get("manifests").Document().Index(9).Delete(),
+	).Commit()
+	assert.Assert(t, err != nil)
+	assert.Equal(t, read(), "# c\nkind: C\n---\nkind: D\n")
+
+	kinds, err := seer.Get("manifests").Document().Index(1).List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, kinds, []string{"kind"})
+
+	// deleting the file deletes all of its documents
+	assert.NilError(t, seer.Get("manifests").Delete().Commit())
+	assert.DeepEqual(t, seer.Dirty(), []string{})
+	_, err = fs.Stat("/manifests.yaml")
+	assert.Assert(t, err != nil)
+}
@@ -3,6 +3,9 @@ package seer
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,6 +18,12 @@ func New(options ...Option) (*Seer, error) {
 
 	s := &Seer{
 		documents: make(map[string]*yaml.Node),
+		dirty:     make(map[string]struct{}),
+		files:     make(map[string]fileState),
+
+		extensions: defaultExtensions,
+
+		pollInterval: defaultPollInterval,
 	}
 
 	for _, opt := range options {
@@ -28,5 +37,20 @@ func New(options ...Option) (*Seer, error) {
 		return nil, errors.New("can't create a Seer instance without a file system")
 	}
 
+	if s.readOnly {
+		s.fs = afero.NewReadOnlyFs(s.fs)
+	}
+
+	if s.fileLock != nil {
+		if !flockSupported {
+			return nil, errors.New("FileLock() is not supported on this platform")
+		}
+		if s.root == "" {
+			return nil, errors.New("FileLock() requires SystemFS()")
+		}
+		s.fileLock.path = filepath.Join(s.root, fileLockName)
+		s.fileLock.readOnly = s.readOnly
+	}
+
 	return s, nil
 }
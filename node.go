@@ -3,9 +3,7 @@ package seer
 import (
 	"errors"
 	"fmt"
-	"strings"
 
-	"github.com/spf13/afero"
 	"github.com/taubyte/utils/maps"
 	pathUtils "github.com/taubyte/utils/path"
 )
@@ -23,6 +21,7 @@ func (n *Query) Fork() *Query {
 		requestedPath: make([]string, len(n.requestedPath)),
 		ops:           make([]op, len(n.ops)),
 		errors:        make([]error, 0),
+		tx:            n.tx,
 	}
 
 	copy(nq.requestedPath, n.requestedPath)
@@ -45,7 +44,7 @@ func (n *Query) Set(value interface{}) *Query {
 func (n *Query) Delete() *Query {
 	n.ops = append(n.ops,
 		op{
-			opType:  opTypeSet,
+			opType:  opTypeDelete,
 			handler: opDelete,
 		},
 	)
@@ -103,47 +102,132 @@ func (n *Query) Clear() *Query {
 	return n
 }
 
+// lock locks the whole Seer unless the lock is already held, by a Tx or by the caller.
+func (n *Query) lock() (unlock func(), err error) {
+	if n.tx != nil || n.held {
+		return func() {}, nil
+	}
+
+	return n.seer.lockAll()
+}
+
+// rlock locks the folder or document read by the query, so reads of different paths run concurrently.
+func (n *Query) rlock() (unlock func(), err error) {
+	if n.tx != nil || n.held {
+		return func() {}, nil
+	}
+
+	n.seer.lock.RLock()
+	unlockPath := n.lockPath(false)
+	return n.seer.lockFile(false, func() {
+		unlockPath()
+		n.seer.lock.RUnlock()
+	})
+}
+
+// wlock locks the folder or document written by the query, so reads of other paths are not
+// blocked. Writers still run one at a time, as they share the journal. Queries with wildcards
+// lock the whole Seer as the paths they write are not known yet.
+func (n *Query) wlock() (unlock func(), err error) {
+	if n.tx != nil || n.held {
+		return func() {}, nil
+	}
+
+	if n.hasGlob() {
+		return n.lock()
+	}
+
+	n.seer.lock.RLock()
+	n.seer.writing.Lock()
+	unlockPath := n.lockPath(true)
+	return n.seer.lockFile(true, func() {
+		unlockPath()
+		n.seer.writing.Unlock()
+		n.seer.lock.RUnlock()
+	})
+}
+
 func (n *Query) Commit() error {
-	n.seer.lock.Lock()
-	defer n.seer.lock.Unlock()
+	unlock, err := n.wlock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return n.commit()
+}
+
+func (n *Query) commit() error {
+	if n.tx != nil && n.tx.done {
+		return errTxDone
+	}
+
 	n.write = true
 	if len(n.errors) > 0 {
 		return fmt.Errorf("%d errors preventing commit", len(n.errors))
 	}
 
+	if n.seer.readOnly {
+		return &PathError{Op: "commit", Path: n.String(), Err: ErrReadOnly}
+	}
+
+	if n.hasGlob() {
+		matches, err := n.glob()
+		if err != nil {
+			return &PathError{Op: "commit", Path: n.String(), Err: err}
+		}
+		return n.seer.commitAll(commitOrder(matches.queries))
+	}
+
+	n.seer.begin()
+	_, _, err := n.run(n.ops)
+	if err != nil {
+		return n.seer.abort(&PathError{Op: "commit", Path: n.String(), Err: err})
+	}
+
+	return n.seer.finish()
+}
+
+// run executes ops and returns the path and node they lead to.
+func (n *Query) run(ops []op) ([]string, *yamlNode, error) {
 	var (
 		path []string  = make([]string, 0)
 		doc  *yamlNode // nil when created here
 		err  error
 	)
-	for _, op := range n.ops {
+	for _, op := range ops {
 		path, doc, err = op.handler(op, n, path, doc)
 		if err != nil {
-			return fmt.Errorf("committing failed with %s", err.Error())
+			return path, doc, err
 		}
 	}
 
-	return nil
+	return path, doc, nil
 }
 
 func (n *Query) Value(dst interface{}) error {
-	n.seer.lock.Lock()
-	defer n.seer.lock.Unlock()
+	unlock, err := n.rlock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if n.tx != nil && n.tx.done {
+		return errTxDone
+	}
+
 	n.write = false
 	if len(n.errors) > 0 {
 		return fmt.Errorf("%d errors preventing getting value", len(n.errors))
 	}
 
-	var (
-		path []string  = make([]string, 0)
-		doc  *yamlNode // nil when created here
-		err  error
-	)
-	for _, op := range n.ops {
-		path, doc, err = op.handler(op, n, path, doc)
-		if err != nil {
-			return fmt.Errorf("Value failed with %s", err.Error())
-		}
+	if n.hasGlob() {
+		return errors.New("a query with wildcards can only be evaluated with Matches()")
+	}
+
+	path, doc, err := n.run(n.ops)
+	if err != nil {
+		return &PathError{Op: "value", Path: n.String(), Err: err}
 	}
 
 	if doc == nil {
@@ -151,24 +235,11 @@ func (n *Query) Value(dst interface{}) error {
 		_path := "/" + pathUtils.Join(path)
 		if st, exist := n.seer.fs.Stat(_path); exist == nil && st.IsDir() {
 			// it's a folder
-			dirFiles, err := afero.ReadDir(n.seer.fs, _path)
+			_dst, err := n.seer.listFolder(_path)
 			if err != nil {
 				return fmt.Errorf("parsing folder `%s` failed with %w", path, err)
 			}
 
-			_dst := make([]string, 0)
-			for _, f := range dirFiles {
-				if f.IsDir() {
-					_dst = append(_dst, f.Name())
-				} else {
-					fname := f.Name()
-					item := strings.TrimSuffix(fname, ".yaml")
-					if item+".yaml" == fname {
-						_dst = append(_dst, item)
-					}
-				}
-			}
-
 			switch idst := dst.(type) {
 			case *interface{}:
 				*idst = _dst
@@ -180,13 +251,13 @@ func (n *Query) Value(dst interface{}) error {
 
 			return nil
 		} else {
-			return fmt.Errorf("no data found for %s", path)
+			return &PathError{Op: "value", Path: n.String(), Err: ErrNotFound}
 		}
 	}
 
 	err = doc.this.Decode(dst)
 	if err != nil {
-		return fmt.Errorf("decode(%T) failed with %s", dst, err)
+		return fmt.Errorf("decode(%T) failed with %w", dst, err)
 	}
 
 	return nil
@@ -196,7 +267,7 @@ func (n *Query) List() ([]string, error) {
 	var val interface{}
 	err := n.Value(&val)
 	if err != nil {
-		return nil, fmt.Errorf("listing keys failed with %s", err)
+		return nil, fmt.Errorf("listing keys failed with %w", err)
 	}
 
 	// Empty value should be an empty list
@@ -3,9 +3,7 @@ package seer
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -14,7 +12,17 @@ import (
 
 func opDelete(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
 	if !query.write {
-		return path, nil, errors.New("failed to call Delete() during a read query")
+		return path, nil, fmt.Errorf("failed to call Delete(): %w", ErrReadOnlyQuery)
+	}
+
+	if query.seer.readOnly {
+		return path, nil, fmt.Errorf("failed to call Delete(): %w", ErrReadOnly)
+	}
+
+	if value != nil && value.parent == nil && value.indexed {
+		// one of the documents of a file holding many
+		query.seer.removeDocument(value.document)
+		return path, nil, nil
 	}
 
 	if value == nil || value.parent == nil {
@@ -28,13 +36,23 @@ func opDelete(this op, query *Query, path []string, value *yamlNode) ([]string,
 func _opDeleteInYaml(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
 
 	if !query.write {
-		return path, nil, errors.New("failed to call Delete() during a read query")
+		return path, nil, fmt.Errorf("failed to call Delete(): %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.parent == nil || value.this == nil {
+		return path, nil, fmt.Errorf("failed to call Delete() outside a value: %w", ErrNotDocument)
 	}
 
-	if value == nil || value.parent == nil || value.prev == nil {
-		return path, nil, errors.New("failed to call Delete() outside a value or document")
+	if value.prev == nil && value.parent.Kind != yaml.SequenceNode {
+		return path, nil, fmt.Errorf("failed to call Delete() outside a mapping or sequence: %w", ErrNotDocument)
 	}
 
+	if err := query.seer.checkUnaliased(value.document, value.this, nil); err != nil {
+		return path, nil, err
+	}
+
+	query.seer.modified(value.document)
+
 	parentNodeContent := value.parent.Content
 	value.parent.Content = make([]*yaml.Node, 0)
 
@@ -45,7 +63,7 @@ func _opDeleteInYaml(this op, query *Query, path []string, value *yamlNode) ([]s
 		value.parent.Content = append(value.parent.Content, elm)
 	}
 
-	return path, &yamlNode{parent: value.parent, prev: nil, this: nil}, nil
+	return path, &yamlNode{parent: value.parent, prev: nil, this: nil, document: value.document}, nil
 }
 
 func _opDeleteInFileSystem(this op, query *Query, _path []string, value *yamlNode) ([]string, *yamlNode, error) {
@@ -56,55 +74,76 @@ func _opDeleteInFileSystem(this op, query *Query, _path []string, value *yamlNod
 	if err != nil {
 
 		// now we know it's a file, it sure is not a yaml file by our standards
-		return _path, nil, fmt.Errorf("unsupported file `%s`. Is this a Document?", path)
+		return _path, nil, fmt.Errorf("deleting `%s` failed with %w", path, errors.Join(ErrNotFound, err))
 
 	}
 
+	if err = query.seer.backup(path); err != nil {
+		return _path, nil, err
+	}
+	events := query.seer.deleted(path)
+
 	if st.IsDir() {
 		// it's a dir => nothing to be done
-		for k := range query.seer.documents {
-			if strings.HasPrefix(k, path) {
-				delete(query.seer.documents, k)
-			}
+		for _, k := range query.seer.cachedDocuments(path + "/") {
+			query.seer.record(k)
+			query.seer.uncacheDocument(k)
 		}
-		err := query.seer.fs.RemoveAll(path)
-		return _path, nil, err
+		if err := query.seer.fs.RemoveAll(path); err != nil {
+			return _path, nil, err
+		}
+		query.seer.notify(events...)
+		return _path, nil, nil
 	}
 	// let's cleanup
-	_, exists := query.seer.documents[path]
-	if exists {
-		// we know it is a file
-		delete(query.seer.documents, path)
+	// we know it is a file
+	for _, key := range query.seer.documentKeys(path) {
+		query.seer.record(key)
+	}
+	query.seer.uncacheDocument(path)
+	if err = query.seer.fs.Remove(path); err != nil {
+		return _path, nil, err
 	}
-	err = query.seer.fs.Remove(path)
-	return _path, nil, err
+	query.seer.notify(events...)
+	return _path, nil, nil
 
 }
 
 func opSetInYaml(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
 
 	if !query.write {
-		return path, nil, errors.New("failed to call Set() during a read query")
+		return path, nil, fmt.Errorf("failed to call Set(): %w", ErrReadOnlyQuery)
 	}
 
 	if value == nil || value.this == nil {
-		return path, nil, errors.New("failed to call Set() outside a document")
+		return path, nil, fmt.Errorf("failed to call Set(): %w", ErrNotDocument)
 	}
 
 	parentNode := value.parent
 	curNode := value.this
 
+	if err := query.seer.checkUnaliased(value.document, curNode, curNode); err != nil {
+		return path, nil, err
+	}
+
+	query.seer.modified(value.document)
+
 	curNode_HeadComment := curNode.HeadComment
 	curNode_LineComment := curNode.LineComment
 	curNode_FootComment := curNode.FootComment
+	curNode_Anchor := curNode.Anchor
 
 	err := curNode.Encode(this.value)
 
 	curNode.HeadComment = curNode_HeadComment
 	curNode.LineComment = curNode_LineComment
 	curNode.FootComment = curNode_FootComment
+	if curNode.Kind != yaml.AliasNode {
+		// aliases of the value now point to the new one
+		curNode.Anchor = curNode_Anchor
+	}
 
-	return path, &yamlNode{parent: parentNode, prev: value.prev, this: curNode}, err
+	return path, &yamlNode{parent: parentNode, prev: value.prev, this: curNode, document: value.document}, err
 }
 
 func opGetOrCreate(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
@@ -123,7 +162,7 @@ func opGetOrCreate(this op, query *Query, path []string, value *yamlNode) ([]str
 func _opGetInYaml(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
 
 	if value == nil || value.this == nil {
-		return path, nil, fmt.Errorf("can not find %s in the empty document %s", this.name, pathUtils.Join(path))
+		return path, nil, fmt.Errorf("can not find %s in the empty document %s: %w", this.name, pathUtils.Join(path), ErrNotFound)
 	}
 
 	path = append(path, this.name)
@@ -131,89 +170,128 @@ func _opGetInYaml(this op, query *Query, path []string, value *yamlNode) ([]stri
 	curNode := value.this
 	if curNode.Kind == yaml.DocumentNode {
 		if len(curNode.Content) != 1 {
-			return path, nil, fmt.Errorf("failed to process empty document at %s", pathUtils.Join(path))
+			return path, nil, fmt.Errorf("failed to process empty document at %s: %w", pathUtils.Join(path), ErrNotFound)
 		}
 		parentNode = curNode
 		curNode = curNode.Content[0]
 	}
+	if curNode.Kind == yaml.AliasNode {
+		if query.write {
+			// copy on write, leaving the anchored value and its other aliases untouched
+			query.seer.modified(value.document)
+			detachAlias(curNode)
+		} else {
+			curNode = resolveAlias(curNode)
+		}
+	}
 	if curNode.Kind == yaml.MappingNode {
 		parentNode = curNode
 		for i := 0; i+1 < len(curNode.Content); i += 2 {
-			if curNode.Content[i].Kind == yaml.ScalarNode && curNode.Content[i].Value == this.name {
+			if curNode.Content[i].Kind == yaml.ScalarNode && curNode.Content[i].Value == this.name && !isMergeKey(curNode.Content[i]) {
 				// we got it
-				return path, &yamlNode{parent: parentNode, prev: curNode.Content[i], this: curNode.Content[i+1]}, nil
+				return path, &yamlNode{parent: parentNode, prev: curNode.Content[i], this: curNode.Content[i+1], document: value.document}, nil
+			}
+		}
+
+		if holder, key, merged := mergedEntry(curNode, this.name); key != nil {
+			if !query.write {
+				return path, &yamlNode{parent: holder, prev: key, this: merged, document: value.document}, nil
 			}
+
+			// copy on write, overriding the merged key
+			query.seer.modified(value.document)
+			key, merged = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: this.name}, detachedCopy(merged)
+			curNode.Content = append(curNode.Content, key, merged)
+			return path, &yamlNode{parent: curNode, prev: key, this: merged, document: value.document}, nil
 		}
 
 		if query.write {
+			query.seer.modified(value.document)
 			parentNode = curNode
 			curNode = &yaml.Node{}
 			curNode.Encode(map[string]interface{}{this.name: nil})
 			parentNode.Content = append(parentNode.Content, curNode.Content...)
-			return path, &yamlNode{parent: parentNode, prev: curNode.Content[0], this: curNode.Content[1]}, nil
+			return path, &yamlNode{parent: parentNode, prev: curNode.Content[0], this: curNode.Content[1], document: value.document, created: true}, nil
 		}
 		// else, we return error
-		return path, nil, fmt.Errorf("can not find %s", pathUtils.Join(path))
+		return path, nil, fmt.Errorf("%s: %w", pathUtils.Join(path), ErrNotFound)
 
 	}
 	if curNode.Kind == yaml.SequenceNode {
-		_idx, err := strconv.ParseInt(this.name, 0, 32)
-		if err != nil {
-			return path, nil, fmt.Errorf("failed to process index %s with %w", this.name, err)
+		_index := len(curNode.Content)
+		if this.name != appendIndex {
+			_idx, err := strconv.ParseInt(this.name, 10, 32)
+			if err != nil {
+				return path, nil, fmt.Errorf("failed to process index %s with %w", this.name, errors.Join(err, ErrNotFound))
+			}
+			_index = int(_idx)
+			if _index < 0 {
+				// counting from the end
+				_index += len(curNode.Content)
+				if _index < 0 {
+					return path, nil, fmt.Errorf("index %d (Length: %d): %w", _idx, len(curNode.Content), ErrIndexOutOfRange)
+				}
+			}
 		}
-		_index := int(_idx)
 		if _index >= len(curNode.Content) {
 			if query.write {
+				query.seer.modified(value.document)
 				parentNode = curNode
 				curNode = &yaml.Node{}
 				curNode.Encode(nil)
 				parentNode.Content = append(parentNode.Content, curNode)
-				return path, &yamlNode{parent: parentNode, prev: nil, this: curNode}, nil
+				return path, &yamlNode{parent: parentNode, prev: nil, this: curNode, document: value.document, created: true}, nil
 			} else {
-				return path, nil, fmt.Errorf("index %d out of range (Length: %d)", _index, len(curNode.Content))
+				return path, nil, fmt.Errorf("index %d (Length: %d): %w", _index, len(curNode.Content), ErrIndexOutOfRange)
 			}
 		}
 
-		return path, &yamlNode{parent: parentNode, prev: nil, this: curNode.Content[_index]}, nil
+		return path, &yamlNode{parent: curNode, prev: nil, this: curNode.Content[_index], document: value.document}, nil
 	}
 
 	if query.write {
-
+		query.seer.modified(value.document)
 		curNode.Encode(map[string]interface{}{this.name: nil})
-		return path, &yamlNode{parent: parentNode, prev: curNode, this: curNode.Content[1]}, nil
+		return path, &yamlNode{parent: parentNode, prev: curNode, this: curNode.Content[1], document: value.document, created: true}, nil
 	}
 	//else
 
-	return path, nil, fmt.Errorf("can not find %s", pathUtils.Join(path))
+	return path, nil, fmt.Errorf("%s: %w", pathUtils.Join(path), ErrNotFound)
 }
 
 func _opGetOrCreateInFileSystem(this op, query *Query, _path []string, value *yamlNode) ([]string, *yamlNode, error) {
 	_path = append(_path, this.name)
 	path := "/" + pathUtils.Join(_path)
-	doc, exists := query.seer.documents[path+".yaml"]
+	file, doc, exists := query.seer.cachedDocumentFile(path)
 	if exists {
-		_path[len(_path)-1] += ".yaml"
-		return _path, &yamlNode{parent: nil, this: doc}, nil
+		_path[len(_path)-1] += query.seer.documentExtension(file)
+		return _path, &yamlNode{parent: nil, this: doc, document: file}, nil
 	}
 	st, err := query.seer.fs.Stat(path)
 	if err != nil {
 		// let's check if we're not looking for a yaml file first
-		st, err = query.seer.fs.Stat(path + ".yaml")
+		file, err = query.seer.findDocumentFile(path)
 		if err != nil {
+			return _path, nil, err
+		}
+		if file == "" {
 			// we assume that the folder does not exit and we create
+			if query.seer.readOnly {
+				return _path, nil, fmt.Errorf("creating directory %s failed with %w", path, ErrReadOnly)
+			}
 			err = query.seer.fs.Mkdir(path, 0750)
 			if err != nil {
 				return _path, nil, fmt.Errorf("creating directory %s failed with %w", path, err)
 			}
+			query.seer.onUndo(func() error { return query.seer.fs.Remove(path) })
+			query.seer.notify(newEvent(Created, path, true))
 			return _path, nil, nil
-		} else if st.IsDir() {
-			return _path, nil, fmt.Errorf("not allowed directory `%s.yaml`", path)
 		}
 
 		// it's a yaml file
-		doc, err := query.seer.loadYamlDocument(path + ".yaml")
-		_path[len(_path)-1] += ".yaml"
-		return _path, &yamlNode{parent: nil, this: doc}, err
+		doc, err := query.seer.loadYamlDocument(file)
+		_path[len(_path)-1] += query.seer.documentExtension(file)
+		return _path, &yamlNode{parent: nil, this: doc, document: file}, err
 
 	}
 	if st.IsDir() {
@@ -221,32 +299,33 @@ func _opGetOrCreateInFileSystem(this op, query *Query, _path []string, value *ya
 		return _path, nil, nil
 	}
 	// now we know it's a file, it sure is not a yaml file by our standards
-	return _path, nil, fmt.Errorf("unsupported file `%s`", path)
+	return _path, nil, fmt.Errorf("`%s`: %w", path, ErrUnsupportedFile)
 }
 
 func _opGetInFileSystem(this op, query *Query, _path []string, value *yamlNode) ([]string, *yamlNode, error) {
 	_path = append(_path, this.name)
 	path := "/" + pathUtils.Join(_path)
-	doc, exists := query.seer.documents[path+".yaml"]
+	file, doc, exists := query.seer.cachedDocumentFile(path)
 	if exists {
-		_path[len(_path)-1] += ".yaml"
-		return _path, &yamlNode{parent: nil, this: doc}, nil
+		_path[len(_path)-1] += query.seer.documentExtension(file)
+		return _path, &yamlNode{parent: nil, this: doc, document: file}, nil
 	}
 	st, err := query.seer.fs.Stat(path)
 	if err != nil {
 		// let's check if we're not looking for a yaml file first
-		st, err = query.seer.fs.Stat(path + ".yaml")
-		if err != nil {
+		file, ferr := query.seer.findDocumentFile(path)
+		if ferr != nil {
+			return _path, nil, ferr
+		}
+		if file == "" {
 			// the folder does not exit
-			return _path, nil, fmt.Errorf("fetching %s failed with %w", path, err)
-		} else if st.IsDir() {
-			return _path, nil, fmt.Errorf("not allowed directory `%s.yaml`", path)
+			return _path, nil, fmt.Errorf("fetching %s failed with %w", path, errors.Join(ErrNotFound, err))
 		}
 
 		// it's a yaml file
-		doc, err := query.seer.loadYamlDocument(path + ".yaml")
-		_path[len(_path)-1] += ".yaml"
-		return _path, &yamlNode{parent: nil, this: doc}, err
+		doc, err := query.seer.loadYamlDocument(file)
+		_path[len(_path)-1] += query.seer.documentExtension(file)
+		return _path, &yamlNode{parent: nil, this: doc, document: file}, err
 
 	}
 	if st.IsDir() {
@@ -254,37 +333,46 @@ func _opGetInFileSystem(this op, query *Query, _path []string, value *yamlNode)
 		return _path, nil, nil
 	}
 	// now we know it's a file, it sure is not a yaml file by our standards
-	return _path, nil, fmt.Errorf("unsupported file `%s`", path)
+	return _path, nil, fmt.Errorf("`%s`: %w", path, ErrUnsupportedFile)
 }
 
 func opCreateDocument(this op, query *Query, _path []string, value *yamlNode) ([]string, *yamlNode, error) {
-	_path = append(_path, this.name+".yaml")
-	path := "/" + pathUtils.Join(_path)
+	name := "/" + pathUtils.Join(append(_path, this.name))
 	// Check for it first
 
-	doc, exists := query.seer.documents[path]
+	path, doc, exists := query.seer.cachedDocumentFile(name)
 	if exists {
-		return _path, &yamlNode{parent: nil, this: doc}, nil
+		return append(_path, this.name+query.seer.documentExtension(path)), &yamlNode{parent: nil, this: doc, document: path}, nil
 	}
 
-	st, err := query.seer.fs.Stat(path)
-	if err == nil {
-		if st.IsDir() {
-			return _path, nil, fmt.Errorf("can't create document: `%s` is a directory", path)
-		}
-	} else { // we need to create
+	path, err := query.seer.findDocumentFile(name)
+	if err != nil {
+		return append(_path, this.name), nil, fmt.Errorf("can't create document: %w", err)
+	}
+
+	if path == "" { // we need to create
+		// new documents get the first extension
+		path = name + query.seer.extensions[0]
 		if query.write {
-			f, err := query.seer.fs.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0640)
+			if query.seer.readOnly {
+				return append(_path, this.name), nil, fmt.Errorf("creating yaml file %s failed with %w", path, ErrReadOnly)
+			}
+			err = query.seer.writeFile(path, nil)
 			if err != nil {
-				return _path, nil, fmt.Errorf("creating yaml file %s failed with %w", path, err)
+				return append(_path, this.name), nil, fmt.Errorf("creating yaml file %s failed with %w", path, err)
 			}
-			defer f.Close()
+			query.seer.onUndo(func() error {
+				query.seer.uncacheDocument(path)
+				return query.seer.fs.Remove(path)
+			})
+			query.seer.notify(newEvent(Created, path, false))
 		} else {
-			return _path, nil, fmt.Errorf("Document: `%s` does not exist", path)
+			return append(_path, this.name), nil, fmt.Errorf("Document: `%s` does not exist: %w", path, ErrNotFound)
 		}
 
 	}
 
+	_path = append(_path, this.name+query.seer.documentExtension(path))
 	doc, err = query.seer.loadYamlDocument(path)
-	return _path, &yamlNode{parent: nil, this: doc}, err
+	return _path, &yamlNode{parent: nil, this: doc, document: path}, err
 }
@@ -1,7 +1,11 @@
 package seer
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"slices"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -19,6 +23,7 @@ func SystemFS(path string) Option {
 			return fmt.Errorf("opening repository failed with %w", err)
 		}
 		s.fs = fs
+		s.root = path
 		return nil
 	}
 }
@@ -37,3 +42,79 @@ func VirtualFS(fs afero.Fs, path string) Option {
 		return nil
 	}
 }
+
+// IOFS opens the io/fs.FS fsys, like an embed.FS, at path. It implies ReadOnly().
+func IOFS(fsys fs.FS, path string) Option {
+	return func(s *Seer) error {
+		if s.fs != nil {
+			return fmt.Errorf("can't combine *Fs() Options")
+		}
+		fs := afero.NewBasePathFs(ioFs{fsys: fsys}, path)
+		_, err := fs.Stat("/")
+		if err != nil {
+			return fmt.Errorf("opening repository failed with %w", err)
+		}
+		s.fs = fs
+		s.readOnly = true
+		return nil
+	}
+}
+
+// ReadOnly makes every write to the Seer fail with ErrReadOnly, and guarantees the file system is never modified.
+func ReadOnly() Option {
+	return func(s *Seer) error {
+		s.readOnly = true
+		return nil
+	}
+}
+
+// FileLock makes the Seer take an advisory lock on a file at its root, shared while reading
+// and exclusive while writing, so processes opening the same folder do not overwrite each
+// other's changes. Documents written by another process are read again once the lock is
+// taken, unless they have changes not synced yet. It requires SystemFS(), and a unix platform.
+func FileLock() Option {
+	return func(s *Seer) error {
+		s.fileLock = &fileLock{}
+		return nil
+	}
+}
+
+// PollInterval sets how often Watch checks for external changes when the file system can not
+// notify it, as with VirtualFS.
+func PollInterval(d time.Duration) Option {
+	return func(s *Seer) error {
+		s.pollInterval = d
+		return nil
+	}
+}
+
+// Extensions sets the extensions of the document files, like `.yaml`, `.yml` and `.json`, by
+// priority: new documents get the first one. Documents keep their extension when written back,
+// with the codec of the extension, see RegisterCodec. It defaults to `.yaml`.
+func Extensions(extensions ...string) Option {
+	return func(s *Seer) error {
+		if len(extensions) == 0 {
+			return errors.New("Extensions() needs at least one extension")
+		}
+
+		for i, ext := range extensions {
+			if err := validExtension(ext); err != nil {
+				return err
+			}
+			if slices.Contains(extensions[:i], ext) {
+				return fmt.Errorf("extension `%s` given twice", ext)
+			}
+		}
+
+		s.extensions = slices.Clone(extensions)
+		return nil
+	}
+}
+
+// Fsync makes Sync flush every written document, and the directory holding it, to stable storage.
+func Fsync() Option {
+	return func(s *Seer) error {
+		s.fsync = true
+		return nil
+	}
+}
@@ -0,0 +1,446 @@
+package seer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to the tree. Paths are JSON Pointers, resolved
+// across folders, documents and YAML nodes like Seer.Pointer does. Either all operations
+// are applied or none is.
+func (s *Seer) ApplyPatch(patch []byte) error {
+	var ops []patchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return fmt.Errorf("parsing json patch failed with %w", err)
+	}
+
+	if s.readOnly {
+		return fmt.Errorf("applying json patch failed with %w", ErrReadOnly)
+	}
+
+	unlock, err := s.lockAll()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	s.begin()
+	for i, op := range ops {
+		if err := s.applyPatchOperation(op); err != nil {
+			return s.abort(fmt.Errorf("patch operation %d (%s %s) failed with %w", i, op.Op, op.Path, err))
+		}
+	}
+
+	return s.finish()
+}
+
+func (s *Seer) applyPatchOperation(op patchOperation) error {
+	var (
+		value *yaml.Node
+		err   error
+	)
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return errors.New("missing value")
+		}
+		if value, err = parsePatchValue(op.Value); err != nil {
+			return err
+		}
+	}
+
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add":
+		return s.patchAdd(tokens, value)
+	case "remove":
+		return s.patchRemove(tokens)
+	case "replace":
+		return s.patchReplace(tokens, value)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return err
+		}
+
+		if value, err = s.patchGet(from); err != nil {
+			return err
+		}
+
+		if op.Op == "move" {
+			if isPrefix(from, tokens) && len(from) < len(tokens) {
+				return errors.New("can not move a value into one of its children")
+			}
+			if err = s.patchRemove(from); err != nil {
+				return err
+			}
+		}
+
+		return s.patchAdd(tokens, value)
+	case "test":
+		current, err := s.patchGet(tokens)
+		if err != nil {
+			return err
+		}
+
+		a, err := jsonValue(current)
+		if err != nil {
+			return err
+		}
+		b, err := jsonValue(value)
+		if err != nil {
+			return err
+		}
+		if !deepEqual(a, b) {
+			return errors.New("test failed")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown operation `%s`", op.Op)
+}
+
+// heldQuery returns a query to use while s.lock is held.
+func (s *Seer) heldQuery(tokens ...string) *Query {
+	q := s.Query()
+	q.held = true
+	for _, token := range tokens {
+		q.Get(token)
+	}
+	return q
+}
+
+// resolve returns the node tokens point to, which is nil for folders.
+func (s *Seer) resolve(tokens []string) (*yamlNode, error) {
+	q := s.heldQuery(tokens...)
+	_, node, err := q.run(q.ops)
+	return node, err
+}
+
+// patchGet returns a copy of the value tokens point to.
+func (s *Seer) patchGet(tokens []string) (*yaml.Node, error) {
+	node, err := s.resolve(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if node == nil || node.this == nil {
+		return nil, fmt.Errorf("folders can not be read as values: %w", ErrNotDocument)
+	}
+
+	value := node.this
+	if value.Kind == yaml.DocumentNode {
+		if len(value.Content) == 0 {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+		value = value.Content[0]
+	}
+
+	return cloneNode(value), nil
+}
+
+func (s *Seer) patchAdd(tokens []string, value *yaml.Node) error {
+	if len(tokens) == 0 {
+		return errors.New("can not replace the root")
+	}
+
+	parentTokens, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, err := s.resolve(parentTokens)
+	if err != nil {
+		return err
+	}
+
+	if parent == nil {
+		// last is a document inside a folder
+		return s.heldQuery(tokens...).Document().Set(value).commit()
+	}
+
+	node := parent.this
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+
+	switch node.Kind {
+	case 0, yaml.DocumentNode, yaml.MappingNode:
+		// 0 is the kind of an empty document
+		return s.heldQuery(tokens...).Set(value).commit()
+	case yaml.SequenceNode:
+		if last == appendIndex {
+			return s.heldQuery(parentTokens...).Append(value).commit()
+		}
+
+		index, err := strconv.Atoi(last)
+		if err != nil || index < 0 {
+			return fmt.Errorf("invalid index %s", last)
+		}
+		return s.heldQuery(parentTokens...).InsertAt(index, value).commit()
+	}
+
+	return fmt.Errorf("can not add %s to a scalar", last)
+}
+
+// patchReplace replaces the existing value tokens point to. Sequence items are overwritten in
+// place instead of being inserted like patchAdd does.
+func (s *Seer) patchReplace(tokens []string, value *yaml.Node) error {
+	if _, err := s.patchGet(tokens); err != nil {
+		return err
+	}
+
+	parent, err := s.resolve(tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+
+	if parent != nil {
+		node := parent.this
+		if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+			node = node.Content[0]
+		}
+		if node.Kind == yaml.SequenceNode {
+			return s.heldQuery(tokens...).Set(value).commit()
+		}
+	}
+
+	return s.patchAdd(tokens, value)
+}
+
+func (s *Seer) patchRemove(tokens []string) error {
+	if len(tokens) == 0 {
+		return errors.New("can not remove the root")
+	}
+
+	if _, err := s.resolve(tokens); err != nil {
+		return err
+	}
+
+	return s.heldQuery(tokens...).Delete().commit()
+}
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to the value of the query.
+// On a folder, the keys of the patch are its documents and sub-folders.
+func (n *Query) ApplyMergePatch(patch []byte) error {
+	node, err := parsePatchValue(patch)
+	if err != nil {
+		return fmt.Errorf("parsing merge patch failed with %w", err)
+	}
+
+	unlock, err := n.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if n.tx != nil && n.tx.done {
+		return errTxDone
+	}
+
+	if len(n.errors) > 0 {
+		return fmt.Errorf("%d errors preventing patching", len(n.errors))
+	}
+
+	if n.seer.readOnly {
+		return &PathError{Op: "merge patch", Path: n.String(), Err: ErrReadOnly}
+	}
+
+	q := n.Fork()
+	q.held = true
+
+	n.seer.begin()
+	if err = n.seer.mergePatch(q, node); err != nil {
+		return n.seer.abort(fmt.Errorf("applying merge patch failed with %w", err))
+	}
+
+	return n.seer.finish()
+}
+
+func (s *Seer) mergePatch(q *Query, patch *yaml.Node) error {
+	if _, target, err := q.reader().run(navigation(q.ops)); err == nil && target == nil {
+		// it's a folder
+		if patch.Kind != yaml.MappingNode {
+			return fmt.Errorf("folder %s can only be patched with an object", q.String())
+		}
+
+		for i := 0; i+1 < len(patch.Content); i += 2 {
+			name, value := patch.Content[i].Value, patch.Content[i+1]
+
+			child := q.Fork().Get(name)
+			_, childNode, err := child.reader().run(navigation(child.ops))
+			exists := err == nil
+			switch {
+			case isNull(value):
+				if exists {
+					err = child.Delete().commit()
+				} else {
+					err = nil
+				}
+			case exists && childNode == nil:
+				err = s.mergePatch(child, value)
+			default:
+				child = q.Fork().Get(name).Document()
+				child.ops = append(child.ops, op{opType: opTypeSet, value: value, handler: opMergePatchInYaml})
+				err = child.commit()
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	q.ops = append(q.ops, op{opType: opTypeSet, value: patch, handler: opMergePatchInYaml})
+	return q.commit()
+}
+
+func opMergePatchInYaml(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to apply a merge patch: %w", ErrReadOnlyQuery)
+	}
+
+	if value == nil || value.this == nil {
+		return path, nil, fmt.Errorf("failed to apply a merge patch: %w", ErrNotDocument)
+	}
+
+	query.seer.modified(value.document)
+
+	target := value.this
+	if target.Kind == yaml.DocumentNode {
+		if len(target.Content) == 0 {
+			target.Content = []*yaml.Node{{}}
+		}
+		target = target.Content[0]
+	}
+	for _, dropped := range mergePatchNode(target, this.value.(*yaml.Node)) {
+		// the journal restores the document when the commit fails
+		if err := query.seer.checkUnaliased(value.document, dropped, nil); err != nil {
+			return path, nil, err
+		}
+	}
+
+	return path, value, nil
+}
+
+// mergePatchNode applies the merge patch to dst, following RFC 7386. It returns the nodes of
+// dst it dropped.
+func mergePatchNode(dst, patch *yaml.Node) (dropped []*yaml.Node) {
+	if patch.Kind != yaml.MappingNode {
+		return mergeNode(dst, cloneNode(patch), true)
+	}
+
+	if dst.Kind != yaml.MappingNode {
+		dropped = append(dropped, dst.Content...)
+		replaceNode(dst, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+
+	for i := 0; i+1 < len(patch.Content); i += 2 {
+		key, value := patch.Content[i], patch.Content[i+1]
+		existing := childNode(dst, key.Value)
+		switch {
+		case isNull(value):
+			dropped = append(dropped, removeKey(dst, key.Value)...)
+		case existing != nil:
+			dropped = append(dropped, mergePatchNode(existing, value)...)
+		default:
+			item := &yaml.Node{}
+			mergePatchNode(item, value)
+			dst.Content = append(dst.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Value}, item)
+		}
+	}
+	return dropped
+}
+
+// removeKey removes key from mapping, returning the key and value nodes removed.
+func removeKey(mapping *yaml.Node, key string) []*yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			removed := []*yaml.Node{mapping.Content[i], mapping.Content[i+1]}
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return removed
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// parsePatchValue parses JSON into a node, keeping the order of keys.
+func parsePatchValue(data []byte) (*yaml.Node, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("invalid json")
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	node := doc.Content[0]
+	resetStyle(node)
+	return node, nil
+}
+
+// resetStyle drops the flow and quoting styles of JSON so values are written as regular YAML.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, c := range node.Content {
+		resetStyle(c)
+	}
+}
+
+func isPrefix(prefix, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// deepEqual compares values decoded from JSON, where numbers are equal regardless of their type.
+func deepEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !deepEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !deepEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return equal(a, b)
+}
@@ -0,0 +1,80 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyPatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/taumobile.yaml", []byte("battery: 100 # kWh\nowners:\n  - sam\n  - alex\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	err = seer.ApplyPatch([]byte(`[
+		{"op": "test", "path": "/cars/taumobile/battery", "value": 100},
+		{"op": "replace", "path": "/cars/taumobile/battery", "value": 120},
+		{"op": "add", "path": "/cars/taumobile/owners/1", "value": "kim"},
+		{"op": "add", "path": "/cars/taumobile/owners/-", "value": "lee"},
+		{"op": "replace", "path": "/cars/taumobile/owners/2", "value": "max"},
+		{"op": "replace", "path": "/cars/taumobile/owners/2", "value": "alex"},
+		{"op": "remove", "path": "/cars/taumobile/owners/0"},
+		{"op": "add", "path": "/cars/volt", "value": {"battery": 60}},
+		{"op": "copy", "from": "/cars/taumobile/owners", "path": "/cars/volt/owners"},
+		{"op": "move", "from": "/cars/volt/battery", "path": "/cars/volt/capacity"}
+	]`))
+	assert.NilError(t, err)
+	assert.NilError(t, seer.Sync())
+
+	data, err := afero.ReadFile(fs, "/cars/taumobile.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "battery: 120 # kWh\nowners:\n    - kim\n    - alex\n    - lee\n")
+
+	data, err = afero.ReadFile(fs, "/cars/volt.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "owners:\n    - kim\n    - alex\n    - lee\ncapacity: 60\n")
+
+	// failing operations roll back the whole patch
+	err = seer.ApplyPatch([]byte(`[
+		{"op": "replace", "path": "/cars/taumobile/battery", "value": 0},
+		{"op": "test", "path": "/cars/taumobile/battery", "value": 120}
+	]`))
+	assert.ErrorContains(t, err, "test failed")
+
+	var battery int
+	assert.NilError(t, seer.Pointer("/cars/taumobile/battery").Value(&battery))
+	assert.Equal(t, battery, 120)
+
+	assert.Assert(t, seer.ApplyPatch([]byte(`[{"op": "remove", "path": "/cars/missing"}]`)) != nil)
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/taumobile.yaml", []byte("# car\nbattery: 100 # kWh\ncolor: red\n"), 0640)
+	afero.WriteFile(fs, "/cars/old.yaml", []byte("battery: 10\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	err = seer.Get("cars").ApplyMergePatch([]byte(`{
+		"taumobile": {"battery": 120, "color": null, "spec": {"seats": 4}},
+		"old": null,
+		"volt": {"battery": 60}
+	}`))
+	assert.NilError(t, err)
+	assert.NilError(t, seer.Sync())
+
+	data, err := afero.ReadFile(fs, "/cars/taumobile.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "# car\nbattery: 120 # kWh\nspec:\n    seats: 4\n")
+
+	data, err = afero.ReadFile(fs, "/cars/volt.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "battery: 60\n")
+
+	_, err = fs.Stat("/cars/old.yaml")
+	assert.Assert(t, err != nil)
+}
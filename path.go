@@ -0,0 +1,249 @@
+package seer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path expressions address seer values with a single string:
+//
+//	cars/electric/taumobile.Battery
+//	services/api.ports[0]
+//
+// Slashes separate folders and documents, dots and brackets separate the keys and
+// sequence indexes inside a document. A `*` makes the segment a wildcard, see Query.Glob.
+// `#n` after a document selects its document n, see Query.Index:
+//
+//	manifests/deploy#1.kind
+//
+// A backslash escapes any of `/.[]*#\`.
+
+type pathSegment struct {
+	name  string
+	key   bool   // inside a document
+	glob  bool   // name is a path.Match pattern
+	index string // document of the file, set by `#n`
+}
+
+func parsePath(expr string) ([]pathSegment, error) {
+	segments := make([]pathSegment, 0)
+	expr = strings.TrimPrefix(expr, "/")
+	if expr == "" {
+		return segments, nil
+	}
+
+	var (
+		name    strings.Builder
+		pattern strings.Builder // name as a path.Match pattern
+		glob    bool            // current segment has a wildcard
+		key     bool            // current segment is a key
+		bracket bool            // inside [...]
+		closed  bool            // a bracket was just closed, expecting a separator
+	)
+
+	push := func(pos int) error {
+		if name.Len() == 0 {
+			return fmt.Errorf("empty segment at position %d of path `%s`", pos, expr)
+		}
+		segment := pathSegment{name: name.String(), key: key, glob: glob}
+		if glob {
+			segment.name = pattern.String()
+		}
+		segments = append(segments, segment)
+		name.Reset()
+		pattern.Reset()
+		glob = false
+		return nil
+	}
+
+	literal := func(c byte) {
+		name.WriteByte(c)
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			pattern.WriteByte('\\')
+		}
+		pattern.WriteByte(c)
+	}
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if closed && c != '/' && c != '.' && c != '[' {
+			return nil, fmt.Errorf("unexpected `%c` after `]` at position %d of path `%s`", c, i, expr)
+		}
+
+		switch {
+		case c == '\\':
+			if i+1 == len(expr) {
+				return nil, fmt.Errorf("dangling escape at the end of path `%s`", expr)
+			}
+			i++
+			literal(expr[i])
+		case bracket:
+			if c == ']' {
+				if err := push(i); err != nil {
+					return nil, err
+				}
+				bracket, closed = false, true
+			} else {
+				literal(c)
+			}
+		case c == '/':
+			if key {
+				return nil, fmt.Errorf("unexpected `/` inside document keys at position %d of path `%s`", i, expr)
+			}
+			if err := push(i); err != nil {
+				return nil, err
+			}
+		case c == '.', c == '[':
+			if !closed {
+				if err := push(i); err != nil {
+					return nil, err
+				}
+			}
+			key, closed, bracket = true, false, c == '['
+		case c == ']':
+			return nil, fmt.Errorf("unexpected `]` at position %d of path `%s`", i, expr)
+		case c == '#' && !key:
+			if glob {
+				return nil, fmt.Errorf("unexpected `#` after a wildcard at position %d of path `%s`", i, expr)
+			}
+			j := i + 1
+			for j < len(expr) && expr[j] >= '0' && expr[j] <= '9' {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("missing document index at position %d of path `%s`", j, expr)
+			}
+			if j < len(expr) && expr[j] != '.' && expr[j] != '[' {
+				return nil, fmt.Errorf("unexpected `%c` after document index at position %d of path `%s`", expr[j], j, expr)
+			}
+			if err := push(i); err != nil {
+				return nil, err
+			}
+			segments[len(segments)-1].index = expr[i+1 : j]
+			i, closed = j-1, true
+			continue
+		case c == '*':
+			glob = true
+			name.WriteByte(c)
+			pattern.WriteByte(c)
+		default:
+			literal(c)
+		}
+
+		if c != ']' || bracket {
+			closed = false
+		}
+	}
+
+	if bracket {
+		return nil, fmt.Errorf("missing `]` in path `%s`", expr)
+	}
+
+	if !closed {
+		if err := push(len(expr)); err != nil {
+			return nil, err
+		}
+	}
+
+	return segments, nil
+}
+
+func escapePathSegment(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; c {
+		case '/', '.', '[', ']', '*', '#', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isIndex(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Path appends the folders, document and keys described by expr to the query.
+func (n *Query) Path(expr string) *Query {
+	segments, err := parsePath(expr)
+	if err != nil {
+		n.errors = append(n.errors, err)
+		return n
+	}
+
+	for i, seg := range segments {
+		if seg.glob {
+			n.Glob(seg.name)
+			continue
+		}
+
+		n.Get(seg.name)
+		if seg.index != "" {
+			index, err := strconv.Atoi(seg.index)
+			if err != nil {
+				n.errors = append(n.errors, err)
+				return n
+			}
+			n.Document().Index(index)
+		} else if !seg.key && i+1 < len(segments) && segments[i+1].key {
+			n.Document()
+		}
+	}
+
+	return n
+}
+
+// String renders the path of the query as a path expression.
+func (n *Query) String() string {
+	var (
+		b     strings.Builder
+		inDoc bool
+	)
+
+	for _, op := range n.ops {
+		switch op.opType {
+		case opTypeGlob:
+			if inDoc {
+				b.WriteByte('.')
+			} else if b.Len() > 0 {
+				b.WriteByte('/')
+			}
+			b.WriteString(op.name)
+		case opTypeGetOrCreate:
+			switch {
+			case inDoc && isIndex(op.name):
+				b.WriteString("[" + op.name + "]")
+			case inDoc:
+				b.WriteString("." + escapePathSegment(op.name))
+			default:
+				if b.Len() > 0 {
+					b.WriteByte('/')
+				}
+				b.WriteString(escapePathSegment(op.name))
+			}
+		case opTypeCreateDocument:
+			if b.Len() > 0 {
+				b.WriteByte('/')
+			}
+			b.WriteString(escapePathSegment(op.name))
+			inDoc = true
+		case opTypeIndex:
+			b.WriteString("#" + op.name)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package seer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	for expr, expected := range map[string][]pathSegment{
+		"cars/electric/taumobile.Battery": {{"cars", false, false, ""}, {"electric", false, false, ""}, {"taumobile", false, false, ""}, {"Battery", true, false, ""}},
+		"/services/api.ports[0]":          {{"services", false, false, ""}, {"api", false, false, ""}, {"ports", true, false, ""}, {"0", true, false, ""}},
+		"list[1][2].name":                 {{"list", false, false, ""}, {"1", true, false, ""}, {"2", true, false, ""}, {"name", true, false, ""}},
+		`doc.a\.b[c.d]`:                   {{"doc", false, false, ""}, {"a.b", true, false, ""}, {"c.d", true, false, ""}},
+		`my\/dir/doc`:                     {{"my/dir", false, false, ""}, {"doc", false, false, ""}},
+		`cars/*/ta\*u*.Battery`:           {{"cars", false, false, ""}, {"*", false, true, ""}, {`ta\*u*`, false, true, ""}, {"Battery", true, false, ""}},
+		"manifests/deploy#1.kind":         {{"manifests", false, false, ""}, {"deploy", false, false, "1"}, {"kind", true, false, ""}},
+		`list#0[2]`:                       {{"list", false, false, "0"}, {"2", true, false, ""}},
+		`a\#b/doc.c#d`:                    {{"a#b", false, false, ""}, {"doc", false, false, ""}, {"c#d", true, false, ""}},
+	} {
+		segments, err := parsePath(expr)
+		assert.NilError(t, err, expr)
+		assert.DeepEqual(t, segments, expected, cmp.AllowUnexported(pathSegment{}))
+	}
+
+	for _, expr := range []string{"a//b", "a..b", "a.b/c", "a[0", "a]", "a[0]b", `a\`, "a#", "a#x", "a#1/b", "a#1b", "#1", "a*#1"} {
+		_, err := parsePath(expr)
+		assert.Assert(t, err != nil, expr)
+	}
+}
+
+func TestPath(t *testing.T) {
+	seer, err := New(fixtureFS(true, "/"))
+	assert.NilError(t, err)
+
+	assert.NilError(t, seer.Path("cars/electric/taumobile.Battery").Set(100).Commit())
+	assert.NilError(t, seer.Path("cars/electric/taumobile.Ports").Set([]int{80, 443}).Commit())
+
+	var battery int
+	assert.NilError(t, seer.Get("cars").Get("electric").Get("taumobile").Get("Battery").Value(&battery))
+	assert.Equal(t, battery, 100)
+
+	var port int
+	assert.NilError(t, seer.Get("cars").Path("electric/taumobile.Ports[1]").Value(&port))
+	assert.Equal(t, port, 443)
+
+	for _, expr := range []string{
+		"cars/electric",
+		"cars/electric/taumobile.Ports[1]",
+		`cars/a\.b.c\/d[0].e`,
+		"manifests/deploy#1.kind",
+		"manifests/deploy#2",
+		`cars/a\#b.c`,
+	} {
+		assert.Equal(t, seer.Path(expr).String(), expr)
+	}
+
+	assert.Equal(t, len(seer.Path("a..b").Errors()), 1)
+
+	assert.NilError(t, seer.Path("manifests/deploy.kind").Set("Deployment").Commit())
+	assert.NilError(t, seer.Path("manifests/deploy#1.kind").Set("Service").Commit())
+
+	var kind string
+	assert.NilError(t, seer.Get("manifests").Get("deploy").Document().Index(1).Get("kind").Value(&kind))
+	assert.Equal(t, kind, "Service")
+
+	query := seer.Get("manifests").Get("deploy").Document().Index(1).Get("kind")
+	assert.NilError(t, seer.Path(query.String()).Value(&kind))
+	assert.Equal(t, kind, "Service")
+}
@@ -0,0 +1,104 @@
+package seer
+
+import (
+	"slices"
+	"sync"
+
+	pathUtils "github.com/taubyte/utils/path"
+)
+
+// pathLocks are read/write locks on the folders and documents of a Seer. Locking a path
+// also locks everything below it, so two locks conflict when one path is an ancestor of the
+// other and at least one of them is held for writing.
+type pathLocks struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	held []*pathLock
+}
+
+type pathLock struct {
+	path  []string
+	write bool
+}
+
+// lock blocks until path can be locked.
+func (l *pathLocks) lock(path []string, write bool) (unlock func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cond == nil {
+		l.cond = sync.NewCond(&l.mu)
+	}
+
+	this := &pathLock{path: path, write: write}
+	for l.conflicts(this) {
+		l.cond.Wait()
+	}
+	l.held = append(l.held, this)
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i, h := range l.held {
+			if h == this {
+				l.held = slices.Delete(l.held, i, i+1)
+				break
+			}
+		}
+		l.cond.Broadcast()
+	}
+}
+
+func (l *pathLocks) conflicts(this *pathLock) bool {
+	for _, h := range l.held {
+		if (h.write || this.write) && (isPrefix(h.path, this.path) || isPrefix(this.path, h.path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lockPath locks the folder or document the query leads to. The path is resolved again once
+// locked, in case a document was created or deleted in between.
+func (n *Query) lockPath(write bool) (unlock func()) {
+	for {
+		path := n.lockedPath()
+		unlock := n.seer.paths.lock(path, write)
+		if slices.Equal(path, n.lockedPath()) {
+			return unlock
+		}
+		unlock()
+	}
+}
+
+// lockedPath returns the path of the folder or document the query leads to. Keys inside a
+// document resolve to the document, and wildcards to the folder or document holding them.
+func (n *Query) lockedPath() []string {
+	path := make([]string, 0, len(n.ops))
+	for _, op := range n.ops {
+		switch op.opType {
+		case opTypeCreateDocument:
+			return append(path, op.name)
+		case opTypeGet, opTypeGetOrCreate:
+			path = append(path, op.name)
+			if n.seer.isDocument(path) {
+				return path
+			}
+		default:
+			return path
+		}
+	}
+	return path
+}
+
+// isDocument tells if there is a document at path.
+func (s *Seer) isDocument(path []string) bool {
+	name := "/" + pathUtils.Join(path)
+	if _, _, cached := s.cachedDocumentFile(name); cached {
+		return true
+	}
+
+	file, err := s.findDocumentFile(name)
+	return err == nil && file != ""
+}
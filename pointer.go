@@ -0,0 +1,48 @@
+package seer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// appendIndex is the JSON Pointer token addressing the position after the last item of a sequence.
+const appendIndex = "-"
+
+// parsePointer splits a RFC 6901 JSON Pointer into its unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("json pointer `%s` must start with `/`", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid escape in json pointer `%s`", ptr)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// Pointer appends the folders, document and keys referenced by the JSON Pointer ptr to the query.
+// The `-` token addresses the end of a sequence, so setting it appends a new item.
+func (n *Query) Pointer(ptr string) *Query {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		n.errors = append(n.errors, err)
+		return n
+	}
+
+	for _, token := range tokens {
+		n.Get(token)
+	}
+
+	return n
+}
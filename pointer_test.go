@@ -0,0 +1,45 @@
+package seer
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePointer(t *testing.T) {
+	for ptr, expected := range map[string][]string{
+		"":                                 {},
+		"/cars/electric/taumobile/Battery": {"cars", "electric", "taumobile", "Battery"},
+		"/a~1b/m~0n/~01":                   {"a/b", "m~n", "~1"},
+		"/list/-":                          {"list", "-"},
+	} {
+		tokens, err := parsePointer(ptr)
+		assert.NilError(t, err, ptr)
+		assert.DeepEqual(t, tokens, expected)
+	}
+
+	for _, ptr := range []string{"cars", "/a~", "/a~2"} {
+		_, err := parsePointer(ptr)
+		assert.Assert(t, err != nil, ptr)
+	}
+}
+
+func TestPointer(t *testing.T) {
+	seer, err := New(fixtureFS(true, "/"))
+	assert.NilError(t, err)
+
+	assert.NilError(t, seer.Get("cars").Get("electric").Get("taumobile").Document().Get("list").Set([]string{"a"}).Commit())
+	assert.NilError(t, seer.Pointer("/cars/electric/taumobile/list/-").Set("b").Commit())
+	assert.NilError(t, seer.Pointer("/cars/electric/taumobile/a~1b").Set("slash").Commit())
+
+	var list []string
+	assert.NilError(t, seer.Pointer("/cars/electric/taumobile/list").Value(&list))
+	assert.DeepEqual(t, list, []string{"a", "b"})
+
+	var val string
+	assert.NilError(t, seer.Get("cars").Get("electric").Get("taumobile").Get("a/b").Value(&val))
+	assert.Equal(t, val, "slash")
+
+	assert.Assert(t, seer.Pointer("/cars/electric/taumobile/list/-").Value(&val) != nil)
+	assert.Assert(t, seer.Pointer("/cars/electric/taumobile/list/0x1").Value(&val) != nil)
+}
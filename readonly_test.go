@@ -0,0 +1,77 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/car.yaml", []byte("name: tau\nlist: [1, 2]\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"), ReadOnly())
+	assert.NilError(t, err)
+
+	var name string
+	assert.NilError(t, seer.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "tau")
+
+	for _, err := range []error{
+		seer.Path("cars/car.name").Set("other").Commit(),
+		seer.Path("cars/car").Delete().Commit(),
+		seer.Path("bikes/bmx.name").Set("bmx").Commit(),
+		seer.Path("cars/car.list").Append(3).Commit(),
+		seer.Batch(seer.Path("cars/car.name").Set("other")).Commit(),
+		seer.Path("cars/car").CopyTo(seer.Path("cars/copy")),
+		seer.ApplyPatch([]byte(`[{"op": "remove", "path": "/cars/car/name"}]`)),
+		seer.Get("cars").ApplyMergePatch([]byte(`{"car": null}`)),
+		seer.Sync(),
+	} {
+		assert.Assert(t, errors.Is(err, ErrReadOnly), err)
+	}
+
+	// handlers never touch the file system, even when called outside Commit
+	q := seer.Path("bikes/bmx")
+	q.write = true
+	_, _, err = q.run(q.ops)
+	assert.Assert(t, errors.Is(err, ErrReadOnly), err)
+
+	_, err = fs.Stat("/bikes")
+	assert.Assert(t, err != nil)
+
+	data, err := afero.ReadFile(fs, "/cars/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "name: tau\nlist: [1, 2]\n")
+}
+
+func TestIOFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/cars/car.yaml": &fstest.MapFile{Data: []byte("name: tau\n")},
+		"config/cars/bus.yaml": &fstest.MapFile{Data: []byte("name: bus\n")},
+	}
+
+	seer, err := New(IOFS(fsys, "config"))
+	assert.NilError(t, err)
+
+	var name string
+	assert.NilError(t, seer.Path("cars/car.name").Value(&name))
+	assert.Equal(t, name, "tau")
+
+	list, err := seer.Get("cars").List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, list, []string{"bus", "car"})
+
+	matches, err := seer.Path("cars/*.name").Matches()
+	assert.NilError(t, err)
+	assert.Equal(t, matches.Len(), 2)
+
+	err = seer.Path("cars/car.name").Set("other").Commit()
+	assert.Assert(t, errors.Is(err, ErrReadOnly), err)
+
+	_, err = New(IOFS(fsys, "missing"))
+	assert.Assert(t, err != nil)
+}
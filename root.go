@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/afero"
@@ -13,6 +15,7 @@ import (
 
 func (s *Seer) Batch(queries ...*Query) *Batch {
 	b := &Batch{
+		seer:    s,
 		queries: make([]*Query, len(queries)),
 	}
 
@@ -22,43 +25,179 @@ func (s *Seer) Batch(queries ...*Query) *Batch {
 }
 
 func (s *Seer) Sync() error {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	if s.readOnly {
+		return fmt.Errorf("syncing failed with %w", ErrReadOnly)
+	}
+
+	// writers are held off so documents are written as a whole, readers are not as encoding
+	// does not modify them
+	s.lock.RLock()
+	s.writing.Lock()
+	unlock, err := s.lockFile(true, func() {
+		s.writing.Unlock()
+		s.lock.RUnlock()
+	})
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if errs := s.validateDocuments(s.dirtyDocuments()); len(errs) > 0 {
+		return fmt.Errorf("validating documents failed with %w", errors.Join(errs...))
+	}
 
-	for docName, doc := range s.documents {
-		f, err := s.fs.OpenFile(docName, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0640)
-		if err != nil {
-			return fmt.Errorf("opening %s failed with %w", docName, err)
+	files := s.dirtyFiles()
+	if errs := s.conflicts("sync", files); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, file := range files {
+		if err := s.writeDocument(file, s.fileDocuments(file)...); err != nil {
+			// the files written before still tell the other processes
+			return errors.Join(err, s.renewGeneration())
 		}
-		defer f.Close()
+		s.notify(newEvent(Modified, file, false))
 
-		enc := yaml.NewEncoder(f)
-		err = enc.Encode(doc)
-		if err != nil {
-			return fmt.Errorf("encoding data to %s failed with %w", docName, err)
+		s.cache.Lock()
+		for key := range s.dirty {
+			if documentFile(key) == file {
+				delete(s.dirty, key)
+			}
 		}
+		s.cache.Unlock()
+	}
+
+	if len(files) > 0 {
+		return s.renewGeneration()
 	}
 	return nil
 }
 
+// lockAll locks the whole Seer.
+func (s *Seer) lockAll() (unlock func(), err error) {
+	s.lock.Lock()
+	return s.lockFile(true, s.lock.Unlock)
+}
+
+// Dirty returns the paths of the documents modified since the last Sync.
+func (s *Seer) Dirty() []string {
+	return s.dirtyFiles()
+}
+
+func (s *Seer) dirtyDocuments() []string {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	docNames := make([]string, 0, len(s.dirty))
+	for docName := range s.dirty {
+		docNames = append(docNames, docName)
+	}
+	sort.Strings(docNames)
+	return docNames
+}
+
+// modified marks the document at path as needing to be written by Sync.
+func (s *Seer) modified(path string) {
+	if path != "" {
+		s.record(path)
+
+		s.cache.Lock()
+		s.dirty[path] = struct{}{}
+		s.cache.Unlock()
+
+		s.notify(newEvent(Modified, path, false))
+	}
+}
+
+// cachedDocument returns the document at path if it is loaded.
+func (s *Seer) cachedDocument(path string) (*yaml.Node, bool) {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	doc, cached := s.documents[path]
+	return doc, cached
+}
+
+// cachedDocuments returns the paths of the loaded documents starting with prefix.
+func (s *Seer) cachedDocuments(prefix string) []string {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	paths := make([]string, 0)
+	for path := range s.documents {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// uncacheDocument forgets the documents of the file at path, along with their pending changes.
+func (s *Seer) uncacheDocument(path string) {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	s.cacheDocuments(path, nil)
+	delete(s.dirty, path)
+}
+
 func (s *Seer) Get(name string) *Query {
 	return s.Query().Get(name)
 }
 
+func (s *Seer) Path(expr string) *Query {
+	return s.Query().Path(expr)
+}
+
+func (s *Seer) Pointer(ptr string) *Query {
+	return s.Query().Pointer(ptr)
+}
+
 func (s *Seer) List() ([]string, error) {
+	s.lock.RLock()
+	unlockPath := s.paths.lock(nil, false)
+	unlock, err := s.lockFile(false, func() {
+		unlockPath()
+		s.lock.RUnlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return s.list()
+}
 
-	list, err := afero.ReadDir(s.fs, "/")
+func (s *Seer) list() ([]string, error) {
+	out, err := s.listFolder("/")
 	if err != nil {
 		return nil, fmt.Errorf("listing seer's root failed with %w", err)
 	}
 
+	return out, nil
+}
+
+// listFolder returns the sub-folders and documents of the folder at path.
+func (s *Seer) listFolder(path string) ([]string, error) {
+	list, err := afero.ReadDir(s.fs, path)
+	if err != nil {
+		return nil, err
+	}
+
 	out := make([]string, 0)
-	for _, s := range list {
-		name := s.Name()
-		if s.IsDir() {
+	seen := make(map[string]bool)
+	for _, f := range list {
+		name := f.Name()
+		if !f.IsDir() && s.isDocumentFile(name) {
+			// a document stored with several extensions is listed once
+			name = strings.TrimSuffix(name, s.documentExtension(name))
+		} else if !f.IsDir() {
+			continue
+		}
+
+		if !seen[name] {
+			seen[name] = true
 			out = append(out, name)
-		} else if strings.HasSuffix(name, ".yaml") {
-			out = append(out, strings.TrimSuffix(name, ".yaml"))
 		}
 	}
 
@@ -73,20 +212,135 @@ func (s *Seer) Query() *Query {
 	}
 }
 
+// loadYamlDocument caches the documents of the file at path, and returns the first one.
 func (s *Seer) loadYamlDocument(path string) (*yaml.Node, error) {
+	docs, state, err := s.readDocumentFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	// another reader may have loaded it meanwhile
+	if doc, cached := s.documents[path]; cached {
+		return doc, nil
+	}
+
+	s.cacheDocuments(path, docs)
+	s.files[path] = state
+	return docs[0], nil
+}
+
+// readYamlDocument parses the documents of the file at path without caching them.
+func (s *Seer) readYamlDocument(path string) ([]*yaml.Node, error) {
+	docs, _, err := s.readDocumentFile(path)
+	return docs, err
+}
+
+// readDocumentFile parses the documents of the file at path, and returns the state of the file
+// they were read from. An empty file holds a single empty document.
+func (s *Seer) readDocumentFile(path string) ([]*yaml.Node, fileState, error) {
 	f, err := s.fs.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("opening yaml file %s failed with %w", path, err)
+		return nil, fileState{}, fmt.Errorf("opening yaml file %s failed with %w", path, err)
 	}
 	defer f.Close()
 
-	root_node := &yaml.Node{}
-	yaml_decoder := yaml.NewDecoder(f)
-	err = yaml_decoder.Decode(root_node)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, fmt.Errorf("processing yaml file %s failed with %w", path, err)
+	st, err := f.Stat()
+	if err != nil {
+		return nil, fileState{}, fmt.Errorf("opening yaml file %s failed with %w", path, err)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fileState{}, fmt.Errorf("reading yaml file %s failed with %w", path, err)
+	}
+
+	docs, err := s.codecOf(path).Decode(data)
+	if err != nil {
+		return nil, fileState{}, fmt.Errorf("processing yaml file %s failed with %w", path, err)
+	}
+	if len(docs) == 0 {
+		docs = append(docs, &yaml.Node{})
+	}
+
+	return docs, newFileState(st, data), nil
+}
+
+// writeDocument encodes docs and atomically replaces the file at path with the result.
+// The data is written to a temporary sibling first, then renamed into place, so a failure
+// at any point leaves the previous content of path untouched.
+func (s *Seer) writeDocument(path string, docs ...*yaml.Node) error {
+	data, err := s.codecOf(path).Encode(docs)
+	if err != nil {
+		return fmt.Errorf("encoding data to %s failed with %w", path, err)
+	}
+
+	if err = s.writeFile(path, data); err != nil {
+		return err
+	}
+
+	st, err := s.fs.Stat(path)
+	if err != nil {
+		return fmt.Errorf("writing %s failed with %w", path, err)
+	}
+
+	s.cache.Lock()
+	s.files[path] = newFileState(st, data)
+	s.cache.Unlock()
+
+	return nil
+}
+
+// writeFile atomically replaces the file at path with data.
+func (s *Seer) writeFile(path string, data []byte) error {
+	dir, name := filepath.Split(path)
+
+	mode := os.FileMode(0640)
+	if st, err := s.fs.Stat(path); err == nil {
+		mode = st.Mode().Perm()
+	}
+
+	f, err := afero.TempFile(s.fs, dir, "."+name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file for %s failed with %w", path, err)
+	}
+	tmpName := f.Name()
+
+	_, err = f.Write(data)
+	if err == nil && s.fsync {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = s.fs.Chmod(tmpName, mode)
+	}
+	if err == nil {
+		err = s.fs.Rename(tmpName, path)
+	}
+	if err != nil {
+		s.fs.Remove(tmpName)
+		return fmt.Errorf("writing %s failed with %w", path, err)
+	}
+
+	if s.fsync {
+		if err = s.syncDir(dir); err != nil {
+			return fmt.Errorf("syncing directory of %s failed with %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Seer) syncDir(dir string) error {
+	d, err := s.fs.Open(dir)
+	if err != nil {
+		return err
 	}
+	defer d.Close()
 
-	s.documents[path] = root_node
-	return root_node, nil
+	return d.Sync()
 }
@@ -0,0 +1,261 @@
+package seer
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	pathpkg "path"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+type schemaBinding struct {
+	pattern string
+	schema  *jsonschema.Schema
+}
+
+// ValidationError reports a value not matching the schema of its document.
+type ValidationError struct {
+	Path    string // path expression of the value
+	Line    int    // 0 when the value was not parsed from a file
+	Column  int
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Line == 0 {
+		// values set by a query have not been parsed from a file yet
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s (line %d, column %d): %s", e.Path, e.Line, e.Column, e.Message)
+}
+
+// Schema binds a JSON Schema to the documents matching pattern, like `cars/*/*`.
+// The pattern uses the syntax of path.Match for each level, and `**` matches any number of levels.
+// Schemas follow draft 2020-12 unless they declare another one with `$schema`.
+//
+// Commits and Syncs leaving a matching document invalid fail.
+func Schema(pattern string, schema []byte) Option {
+	return func(s *Seer) error {
+		url := "seer://schemas/" + strconv.Itoa(len(s.schemas)) + ".json"
+
+		c := jsonschema.NewCompiler()
+		c.Draft = jsonschema.Draft2020
+		if err := c.AddResource(url, bytes.NewReader(schema)); err != nil {
+			return fmt.Errorf("loading schema for `%s` failed with %w", pattern, err)
+		}
+
+		compiled, err := c.Compile(url)
+		if err != nil {
+			return fmt.Errorf("compiling schema for `%s` failed with %w", pattern, err)
+		}
+
+		s.schemas = append(s.schemas, schemaBinding{pattern: strings.Trim(pattern, "/"), schema: compiled})
+		return nil
+	}
+}
+
+// Validate checks every document of the tree against the schemas bound to it.
+func (s *Seer) Validate() []error {
+	s.lock.RLock()
+	s.writing.Lock()
+	unlock, err := s.lockFile(false, func() {
+		s.writing.Unlock()
+		s.lock.RUnlock()
+	})
+	if err != nil {
+		return []error{err}
+	}
+	defer unlock()
+
+	errs := make([]error, 0)
+	err = afero.Walk(s.fs, "/", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !s.isDocumentFile(info.Name()) || len(s.schemasOf(path)) == 0 {
+			return nil
+		}
+
+		docs := s.fileDocuments(path)
+		if len(docs) == 0 {
+			if docs, err = s.readYamlDocument(path); err != nil {
+				errs = append(errs, err)
+				return nil
+			}
+		}
+
+		for i, doc := range docs {
+			errs = append(errs, s.validateDocument(documentKey(path, i), doc)...)
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("walking seer's root failed with %w", err))
+	}
+
+	return errs
+}
+
+// validateDocuments checks the cached documents at paths.
+func (s *Seer) validateDocuments(paths []string) []error {
+	errs := make([]error, 0)
+	for _, path := range paths {
+		if doc, ok := s.cachedDocument(path); ok {
+			errs = append(errs, s.validateDocument(path, doc)...)
+		}
+	}
+	return errs
+}
+
+func (s *Seer) schemasOf(path string) []*jsonschema.Schema {
+	name := documentName(path)
+
+	schemas := make([]*jsonschema.Schema, 0)
+	for _, b := range s.schemas {
+		if matchPath(b.pattern, name) {
+			schemas = append(schemas, b.schema)
+		}
+	}
+	return schemas
+}
+
+func (s *Seer) validateDocument(path string, doc *yaml.Node) []error {
+	schemas := s.schemasOf(path)
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	name := documentName(path)
+	value, err := jsonValue(doc)
+	if err != nil {
+		return []error{fmt.Errorf("converting %s failed with %w", name, err)}
+	}
+
+	errs := make([]error, 0)
+	for _, schema := range schemas {
+		verr, ok := schema.Validate(value).(*jsonschema.ValidationError)
+		if !ok {
+			continue
+		}
+
+		for _, leaf := range leafErrors(verr) {
+			errs = append(errs, locate(name, doc, leaf.InstanceLocation, leaf.Message))
+		}
+	}
+
+	return errs
+}
+
+func leafErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	leaves := make([]*jsonschema.ValidationError, 0)
+	for _, cause := range err.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
+
+// locate builds the ValidationError of the value at the JSON Pointer ptr inside the document doc.
+func locate(name string, doc *yaml.Node, ptr string, message string) *ValidationError {
+	e := &ValidationError{Path: name, Line: doc.Line, Column: doc.Column, Message: message}
+
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+		e.Line, e.Column = node.Line, node.Column
+	}
+
+	tokens, _ := parsePointer(ptr)
+	for _, token := range tokens {
+		node = childNode(node, token)
+		if node == nil {
+			break
+		}
+
+		if isIndex(token) {
+			e.Path += "[" + token + "]"
+		} else {
+			e.Path += "." + escapePathSegment(token)
+		}
+		e.Line, e.Column = node.Line, node.Column
+	}
+
+	return e
+}
+
+// childNode returns the value of the key or index name in node, or nil.
+func childNode(node *yaml.Node, name string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if idx, err := strconv.Atoi(name); err == nil && idx >= 0 && idx < len(node.Content) {
+			return node.Content[idx]
+		}
+	}
+	return nil
+}
+
+// jsonValue converts node to the types produced by decoding JSON.
+func jsonValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return jsonValue(node.Content[0])
+	case yaml.AliasNode:
+		return jsonValue(node.Alias)
+	case yaml.MappingNode:
+		entries := mappingEntries(node)
+		m := make(map[string]interface{}, len(entries)/2)
+		for i := 0; i+1 < len(entries); i += 2 {
+			v, err := jsonValue(entries[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[entries[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		l := make([]interface{}, len(node.Content))
+		for i, c := range node.Content {
+			v, err := jsonValue(c)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = v
+		}
+		return l, nil
+	}
+
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case nil, bool, string, int, int64, uint64, float64:
+		return v, nil
+	}
+	return node.Value, nil
+}
+
+// documentName returns the seer path of the document stored at path.
+func documentName(path string) string {
+	file := documentFile(path)
+	return strings.TrimPrefix(strings.TrimSuffix(file, pathpkg.Ext(file)), "/")
+}
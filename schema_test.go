@@ -0,0 +1,57 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+var carSchema = []byte(`{
+	"type": "object",
+	"properties": {
+		"Battery": {"type": "integer", "minimum": 0},
+		"Range": {"type": "integer"}
+	},
+	"required": ["Battery"]
+}`)
+
+func TestSchema(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cars/electric/broken.yaml", []byte("# no battery\nRange: 400\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"), Schema("cars/*/*", carSchema))
+	assert.NilError(t, err)
+
+	errs := seer.Validate()
+	assert.Equal(t, len(errs), 1)
+
+	var verr *ValidationError
+	assert.Assert(t, errors.As(errs[0], &verr))
+	assert.Equal(t, verr.Path, "cars/electric/broken")
+	assert.Equal(t, verr.Line, 2)
+	assert.Equal(t, verr.Column, 1)
+
+	assert.NilError(t, seer.Path("cars/electric/taumobile.Battery").Set(100).Commit())
+
+	err = seer.Path("cars/electric/taumobile.Battery").Set(-1).Commit()
+	assert.Assert(t, errors.As(err, &verr))
+	assert.Equal(t, verr.Path, "cars/electric/taumobile.Battery")
+
+	var battery int
+	assert.NilError(t, seer.Path("cars/electric/taumobile.Battery").Value(&battery))
+	assert.Equal(t, battery, 100)
+
+	// intermediate states of a batch are not validated
+	err = seer.Batch(
+		seer.Path("cars/gas/guzzler.Range").Set(600),
+		seer.Path("cars/gas/guzzler.Battery").Set(0),
+	).Commit()
+	assert.NilError(t, err)
+
+	// documents outside the pattern are not validated
+	assert.NilError(t, seer.Path("bikes/bmx.Battery").Set("none").Commit())
+
+	assert.NilError(t, seer.Sync())
+}
@@ -0,0 +1,149 @@
+package seer
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Append adds value at the end of the sequence. A value just created by the query, like a
+// missing key, becomes a sequence.
+func (n *Query) Append(value interface{}) *Query {
+	return n.insert(appendIndex, value)
+}
+
+// InsertAt inserts value before the item at index in the sequence, like Python's list.insert:
+// negative indexes count from the end, -1 inserting before the last item. Unlike Python,
+// indexes out of range fail. The query then points to the new item.
+func (n *Query) InsertAt(index int, value interface{}) *Query {
+	return n.insert(strconv.Itoa(index), value)
+}
+
+func (n *Query) insert(index string, value interface{}) *Query {
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			name:    index,
+			value:   value,
+			handler: opInsertInSequence,
+		},
+	)
+	return n
+}
+
+// RemoveAt removes the item at index from the sequence. Negative indexes count from the end.
+func (n *Query) RemoveAt(index int) *Query {
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeSet,
+			name:    strconv.Itoa(index),
+			handler: opRemoveFromSequence,
+		},
+	)
+	return n
+}
+
+// sequenceIndex converts name to an index among length positions. Negative indexes count
+// from the end.
+func sequenceIndex(name string, length int) (int, error) {
+	index, err := strconv.Atoi(name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to process index %s with %w", name, err)
+	}
+
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return 0, fmt.Errorf("index %s (Length: %d): %w", name, length, ErrIndexOutOfRange)
+	}
+
+	return index, nil
+}
+
+// sequenceOf returns the sequence node value points to.
+func sequenceOf(value *yamlNode) (*yaml.Node, error) {
+	if value == nil || value.this == nil {
+		return nil, ErrNotDocument
+	}
+
+	node := value.this
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s value: %w", node.ShortTag(), ErrNotSequence)
+	}
+
+	return node, nil
+}
+
+func opInsertInSequence(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to insert: %w", ErrReadOnlyQuery)
+	}
+
+	if value != nil && value.created && value.this.ShortTag() == "!!null" {
+		// a missing key or item, created by the query
+		if err := query.seer.checkUnaliased(value.document, value.this, value.this); err != nil {
+			return path, nil, err
+		}
+		query.seer.modified(value.document)
+		value.this.Kind, value.this.Tag, value.this.Value = yaml.SequenceNode, "!!seq", ""
+	}
+
+	seq, err := sequenceOf(value)
+	if err != nil {
+		return path, nil, err
+	}
+
+	index := len(seq.Content)
+	if this.name != appendIndex {
+		index, _ = strconv.Atoi(this.name)
+		if index < 0 {
+			index += len(seq.Content)
+		}
+		if index < 0 || index > len(seq.Content) {
+			return path, nil, fmt.Errorf("index %s (Length: %d): %w", this.name, len(seq.Content), ErrIndexOutOfRange)
+		}
+	}
+
+	item := &yaml.Node{}
+	if err = item.Encode(this.value); err != nil {
+		return path, nil, err
+	}
+
+	query.seer.modified(value.document)
+	seq.Content = append(seq.Content, nil)
+	copy(seq.Content[index+1:], seq.Content[index:])
+	seq.Content[index] = item
+
+	return append(path, strconv.Itoa(index)), &yamlNode{parent: seq, this: item, document: value.document}, nil
+}
+
+func opRemoveFromSequence(this op, query *Query, path []string, value *yamlNode) ([]string, *yamlNode, error) {
+	if !query.write {
+		return path, nil, fmt.Errorf("failed to remove: %w", ErrReadOnlyQuery)
+	}
+
+	seq, err := sequenceOf(value)
+	if err != nil {
+		return path, nil, err
+	}
+
+	index, err := sequenceIndex(this.name, len(seq.Content))
+	if err != nil {
+		return path, nil, err
+	}
+
+	if err = query.seer.checkUnaliased(value.document, seq.Content[index], nil); err != nil {
+		return path, nil, err
+	}
+
+	query.seer.modified(value.document)
+	seq.Content = append(seq.Content[:index], seq.Content[index+1:]...)
+
+	return path, &yamlNode{parent: value.parent, prev: value.prev, this: value.this, document: value.document}, nil
+}
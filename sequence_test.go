@@ -0,0 +1,59 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func TestSequence(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/hosts.yaml", []byte("ports:\n  - 80 # http\n  - 443\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	ports := func() []int {
+		var val []int
+		assert.NilError(t, seer.Get("hosts").Get("ports").Value(&val))
+		return val
+	}
+
+	assert.NilError(t, seer.Get("hosts").Get("ports").Append(8080).Commit())
+	assert.DeepEqual(t, ports(), []int{80, 443, 8080})
+
+	assert.NilError(t, seer.Get("hosts").Get("ports").InsertAt(0, 22).Commit())
+	// before the last item, like Python's insert
+	assert.NilError(t, seer.Get("hosts").Get("ports").InsertAt(-1, 8443).Commit())
+	assert.DeepEqual(t, ports(), []int{22, 80, 443, 8443, 8080})
+
+	var port int
+	assert.NilError(t, seer.Get("hosts").Get("ports").Get("-1").Value(&port))
+	assert.Equal(t, port, 8080)
+	assert.Assert(t, seer.Get("hosts").Get("ports").Get("-6").Value(&port) != nil)
+
+	assert.NilError(t, seer.Get("hosts").Get("ports").RemoveAt(-1).Commit())
+	assert.NilError(t, seer.Get("hosts").Get("ports").Get("-2").Delete().Commit())
+	assert.DeepEqual(t, ports(), []int{22, 80, 8443})
+
+	assert.Assert(t, seer.Get("hosts").Get("ports").RemoveAt(3).Commit() != nil)
+	assert.Assert(t, seer.Get("hosts").Get("ports").InsertAt(5, 1).Commit() != nil)
+	assert.DeepEqual(t, ports(), []int{22, 80, 8443})
+
+	assert.NilError(t, seer.Get("hosts").Get("ports").InsertAt(-3, 21).Commit())
+	assert.NilError(t, seer.Get("hosts").Get("ports").InsertAt(4, 9000).Commit())
+	assert.DeepEqual(t, ports(), []int{21, 22, 80, 8443, 9000})
+	assert.NilError(t, seer.Get("hosts").Get("ports").RemoveAt(0).RemoveAt(-1).Commit())
+
+	// a missing key becomes a sequence, other values are not one
+	assert.NilError(t, seer.Get("hosts").Get("domains").Append("tau.link").Commit())
+	err = seer.Get("hosts").Get("domains").Get("0").Append("x").Commit()
+	assert.Assert(t, errors.Is(err, ErrNotSequence), err)
+
+	assert.NilError(t, seer.Sync())
+	data, err := afero.ReadFile(fs, "/hosts.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "ports:\n    - 22\n    - 80 # http\n    - 8443\ndomains:\n    - tau.link\n")
+}
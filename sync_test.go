@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"gotest.tools/v3/assert"
 )
 
 func TestSync(t *testing.T) {
@@ -62,3 +64,56 @@ func TestSync(t *testing.T) {
 		t.Error("Did not find inDocument. ", err)
 	}
 }
+
+func TestSyncAtomic(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/car.yaml", []byte("# header\nbattery: 100\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"), Fsync())
+	assert.NilError(t, err)
+
+	var battery int
+	assert.NilError(t, seer.Get("car").Get("battery").Value(&battery))
+	assert.Equal(t, battery, 100)
+
+	// a node of unknown kind can not be encoded
+	seer.documents["/car.yaml"].Content[0].Content[1] = &yaml.Node{Kind: 99}
+	seer.modified("/car.yaml")
+	assert.Assert(t, seer.Sync() != nil)
+
+	data, err := afero.ReadFile(fs, "/car.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "# header\nbattery: 100\n")
+
+	files, err := afero.ReadDir(fs, "/")
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 1)
+}
+
+func TestSyncDirty(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/read.yaml", []byte("a:   1\n"), 0640)
+	afero.WriteFile(fs, "/write.yaml", []byte("a:   1\n"), 0640)
+
+	seer, err := New(VirtualFS(fs, "/"))
+	assert.NilError(t, err)
+
+	var val int
+	assert.NilError(t, seer.Get("read").Get("a").Value(&val))
+	assert.NilError(t, seer.Get("write").Get("a").Value(&val))
+	assert.Equal(t, len(seer.Dirty()), 0)
+
+	assert.NilError(t, seer.Get("write").Get("b").Set(2).Commit())
+	assert.DeepEqual(t, seer.Dirty(), []string{"/write.yaml"})
+
+	assert.NilError(t, seer.Sync())
+	assert.Equal(t, len(seer.Dirty()), 0)
+
+	data, err := afero.ReadFile(fs, "/read.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "a:   1\n")
+
+	data, err = afero.ReadFile(fs, "/write.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "a: 1\nb: 2\n")
+}
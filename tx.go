@@ -0,0 +1,58 @@
+package seer
+
+import "errors"
+
+// Tx runs fn as a transaction. Queries built from tx see the changes made by the
+// previous ones, while the rest of the program sees none of them until fn returns nil.
+// If fn returns an error or panics, every change made through tx is rolled back.
+//
+// The Seer is locked for the whole transaction, so fn must not use queries that are not
+// built from tx.
+func (s *Seer) Tx(fn func(tx *Tx) error) (err error) {
+	unlock, err := s.lockAll()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tx := &Tx{seer: s}
+	defer func() {
+		tx.done = true
+		if r := recover(); r != nil {
+			s.rollback()
+			panic(r)
+		}
+	}()
+
+	s.begin()
+	if err = fn(tx); err != nil {
+		return s.abort(err)
+	}
+
+	return s.finish()
+}
+
+func (tx *Tx) Get(name string) *Query {
+	return tx.Query().Get(name)
+}
+
+func (tx *Tx) Query() *Query {
+	q := tx.seer.Query()
+	q.tx = tx
+	return q
+}
+
+func (tx *Tx) Batch(queries ...*Query) *Batch {
+	b := tx.seer.Batch(queries...)
+	b.tx = tx
+	return b
+}
+
+func (tx *Tx) List() ([]string, error) {
+	if tx.done {
+		return nil, errTxDone
+	}
+	return tx.seer.list()
+}
+
+var errTxDone = errors.New("transaction is already done")
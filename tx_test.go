@@ -0,0 +1,51 @@
+package seer
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTx(t *testing.T) {
+	seer, err := New(fixtureFS(true, "/"))
+	assert.NilError(t, err)
+
+	assert.NilError(t, seer.Get("car").Document().Get("battery").Set(100).Commit())
+
+	abort := errors.New("abort")
+	err = seer.Tx(func(tx *Tx) error {
+		var battery int
+		assert.NilError(t, tx.Get("car").Get("battery").Value(&battery))
+		assert.Equal(t, battery, 100)
+
+		assert.NilError(t, tx.Get("car").Get("battery").Set(battery/2).Commit())
+		assert.NilError(t, tx.Get("bike").Document().Set("new").Commit())
+
+		assert.NilError(t, tx.Get("car").Get("battery").Value(&battery))
+		assert.Equal(t, battery, 50)
+
+		return abort
+	})
+	assert.ErrorIs(t, err, abort)
+
+	var battery int
+	assert.NilError(t, seer.Get("car").Get("battery").Value(&battery))
+	assert.Equal(t, battery, 100)
+
+	items, err := seer.List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []string{"car"})
+
+	var stale *Query
+	err = seer.Tx(func(tx *Tx) error {
+		stale = tx.Get("car").Get("battery")
+		return stale.Fork().Set(75).Commit()
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, seer.Get("car").Get("battery").Value(&battery))
+	assert.Equal(t, battery, 75)
+
+	assert.ErrorIs(t, stale.Value(&battery), errTxDone)
+}
@@ -2,6 +2,7 @@ package seer
 
 import (
 	"sync"
+	"time"
 
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v3"
@@ -11,15 +12,36 @@ type Document interface{}
 
 type Seer struct {
 	fs        afero.Fs
-	lock      sync.Mutex
+	lock      sync.RWMutex // held for reading by queries, which lock their paths, and for writing by Tx, Batch...
+	writing   sync.Mutex   // serializes the writers holding lock for reading, as they share the journal
+	paths     pathLocks
+	cache     sync.Mutex // guards documents and dirty, as readers load documents concurrently
 	documents map[string]*yaml.Node
+	dirty     map[string]struct{}  // documents modified since the last Sync
+	files     map[string]fileState // state of the files the documents were read from or written to
+	fsync     bool
+	readOnly  bool
+	root      string    // path of the root on the OS file system, set by SystemFS
+	fileLock  *fileLock // set by FileLock
+	journal   *journal  // set while changes are being committed
+	schemas   []schemaBinding
+
+	extensions []string         // extensions of the document files, by priority
+	codecs     map[string]Codec // set by RegisterCodec
+
+	watchLock    sync.Mutex
+	watchers     []*watcher
+	pollInterval time.Duration
 }
 
 const (
 	opTypeGet            = 1
 	opTypeCreateDocument = 2
 	opTypeCreateFolder   = 3 // TODO: Either implement or delete
+	opTypeIndex          = 4
 	opTypeSet            = 16
+	opTypeDelete         = 17
+	opTypeGlob           = 32
 	opTypeGetOrCreate    = 42
 )
 
@@ -27,6 +49,8 @@ type op struct {
 	opType  int
 	name    string
 	value   interface{}
+	filter  func(name string, q *Query) bool // narrows the children matched by a wildcard
+	prune   bool                             // drop what is missing from value when merging
 	handler opHandler
 }
 
@@ -34,6 +58,10 @@ type yamlNode struct {
 	parent *yaml.Node // prant
 	prev   *yaml.Node // previous -- genrally contains name
 	this   *yaml.Node // node with data
+
+	document string // path of the document holding the node
+	indexed  bool   // the document was selected with Index
+	created  bool   // the value was just created, as a null, by the query
 }
 
 type opHandler func(this op, node *Query, path []string /*returned by previous op*/, value *yamlNode /* value passed by parent*/) ( /*path*/ []string /*value*/, *yamlNode, error)
@@ -44,8 +72,17 @@ type Query struct {
 	requestedPath []string // is built by the Gets
 	ops           []op
 	errors        []error
+	tx            *Tx  // set when built from a Tx, which holds the lock
+	held          bool // the lock is held by the caller, as for queries given to filters
+}
+
+type Tx struct {
+	seer *Seer
+	done bool
 }
 
 type Batch struct {
+	seer    *Seer
+	tx      *Tx // set when built from a Tx, which holds the lock
 	queries []*Query
 }
@@ -0,0 +1,342 @@
+package seer
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/afero"
+)
+
+// defaultPollInterval is how often a watcher checks the file system when it can not be notified.
+const defaultPollInterval = time.Second
+
+type EventType int
+
+const (
+	Created EventType = iota + 1
+	Modified
+	Deleted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Created:
+		return "created"
+	case Modified:
+		return "modified"
+	case Deleted:
+		return "deleted"
+	}
+	return "unknown"
+}
+
+// Event reports a change to a folder or a document.
+type Event struct {
+	Type EventType
+	Path string // path of the folder or document, like `cars/electric/taumobile`
+	Kind Kind   // KindFolder or KindDocument
+}
+
+func newEvent(typ EventType, path string, dir bool) Event {
+	if dir {
+		return Event{Type: typ, Path: strings.TrimPrefix(path, "/"), Kind: KindFolder}
+	}
+	return Event{Type: typ, Path: documentName(path), Kind: KindDocument}
+}
+
+// watcher delivers the events matching its pattern. Events are queued so committing never
+// waits for a slow receiver.
+type watcher struct {
+	seer    *Seer
+	pattern string
+
+	lock   sync.Mutex
+	queue  []Event
+	files  map[string]watchedFile // state of the file system after the last event
+	signal chan struct{}
+}
+
+type watchedFile struct {
+	dir     bool
+	modTime time.Time
+	size    int64
+}
+
+// Watch emits an event whenever a folder or document matching pattern is created, modified or
+// deleted, until ctx is done. Patterns are matched like those of Schema. Changes made through the
+// Seer are emitted once committed or synced. Changes made by other programs are picked up through
+// file system notifications with SystemFS, and by polling otherwise.
+func (s *Seer) Watch(ctx context.Context, pattern string) (<-chan Event, error) {
+	w := &watcher{
+		seer:    s,
+		pattern: strings.Trim(pattern, "/"),
+		signal:  make(chan struct{}, 1),
+	}
+	if w.pattern == "" {
+		w.pattern = recursiveGlob
+	}
+
+	var err error
+	if w.files, err = w.scan(); err != nil {
+		return nil, err
+	}
+
+	var notifier *fsnotify.Watcher
+	if s.root != "" {
+		if notifier, err = fsnotify.NewWatcher(); err == nil {
+			w.notify(notifier, w.files)
+		} else {
+			// polling still works
+			notifier = nil
+		}
+	}
+
+	s.watchLock.Lock()
+	s.watchers = append(s.watchers, w)
+	s.watchLock.Unlock()
+
+	out := make(chan Event)
+	go w.run(ctx, notifier, out)
+
+	return out, nil
+}
+
+func (w *watcher) run(ctx context.Context, notifier *fsnotify.Watcher, out chan<- Event) {
+	defer close(out)
+	defer w.seer.unwatch(w)
+
+	var (
+		poll   <-chan time.Time
+		events chan fsnotify.Event
+		errs   chan error
+	)
+	if notifier != nil {
+		defer notifier.Close()
+		events, errs = notifier.Events, notifier.Errors
+	} else {
+		ticker := time.NewTicker(w.seer.pollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		for _, e := range w.pop() {
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.signal:
+		case <-poll:
+			w.check(nil)
+		case <-events:
+			w.check(notifier)
+		case <-errs:
+			// events were lost, so look at everything again
+			w.check(notifier)
+		}
+	}
+}
+
+// pop returns the queued events.
+func (w *watcher) pop() []Event {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	queue := w.queue
+	w.queue = nil
+	return queue
+}
+
+// push queues the events matching the pattern.
+func (w *watcher) push(events ...Event) {
+	w.lock.Lock()
+	for _, e := range events {
+		if matchPath(w.pattern, e.Path) {
+			w.queue = append(w.queue, e)
+		}
+	}
+	w.lock.Unlock()
+
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+// check emits the changes made to the file system since the last check.
+func (w *watcher) check(notifier *fsnotify.Watcher) {
+	files, err := w.scan()
+	if err != nil {
+		return
+	}
+
+	w.lock.Lock()
+	created := make(map[string]watchedFile)
+	events := make([]Event, 0)
+	for path, f := range files {
+		old, exists := w.files[path]
+		switch {
+		case !exists:
+			created[path] = f
+			events = append(events, newEvent(Created, path, f.dir))
+		case !f.dir && (!f.modTime.Equal(old.modTime) || f.size != old.size):
+			events = append(events, newEvent(Modified, path, f.dir))
+		}
+	}
+	for path, f := range w.files {
+		if _, exists := files[path]; !exists {
+			events = append(events, newEvent(Deleted, path, f.dir))
+		}
+	}
+	w.files = files
+	w.lock.Unlock()
+
+	if notifier != nil {
+		w.notify(notifier, created)
+	}
+
+	w.push(events...)
+}
+
+// scan returns the state of the folders and documents of the file system. Writers are held off
+// so changes being committed are not mistaken for external ones.
+func (w *watcher) scan() (map[string]watchedFile, error) {
+	w.seer.lock.RLock()
+	defer w.seer.lock.RUnlock()
+	w.seer.writing.Lock()
+	defer w.seer.writing.Unlock()
+
+	files := make(map[string]watchedFile)
+	err := afero.Walk(w.seer.fs, "/", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == "/" || (!info.IsDir() && !w.seer.isDocumentFile(info.Name())) {
+			return nil
+		}
+
+		files[path] = watchedFile{dir: info.IsDir(), modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+
+	return files, err
+}
+
+// notify asks notifier to report the changes made to the root and the folders in files.
+func (w *watcher) notify(notifier *fsnotify.Watcher, files map[string]watchedFile) {
+	if len(notifier.WatchList()) == 0 {
+		notifier.Add(w.seer.root)
+	}
+
+	for path, f := range files {
+		if f.dir {
+			notifier.Add(filepath.Join(w.seer.root, filepath.FromSlash(path)))
+		}
+	}
+}
+
+// seen records the state of the file system after events made through the Seer, so they are not
+// emitted again as external changes.
+func (w *watcher) seen(events []Event) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, e := range events {
+		paths := []string{"/" + e.Path}
+		if e.Kind == KindDocument {
+			paths = paths[:0]
+			for _, ext := range w.seer.extensions {
+				paths = append(paths, "/"+e.Path+ext)
+			}
+		}
+
+		for _, path := range paths {
+			if st, err := w.seer.fs.Stat(path); err == nil {
+				w.files[path] = watchedFile{dir: st.IsDir(), modTime: st.ModTime(), size: st.Size()}
+				continue
+			}
+
+			for p := range w.files {
+				if p == path || strings.HasPrefix(p, path+"/") {
+					delete(w.files, p)
+				}
+			}
+		}
+	}
+}
+
+// notify reports changes made through the Seer. While committing, they are held by the journal
+// until they are kept for good.
+func (s *Seer) notify(events ...Event) {
+	if j := s.journal; j != nil {
+		for _, e := range events {
+			if !slices.Contains(j.events, e) {
+				j.events = append(j.events, e)
+			}
+		}
+		return
+	}
+
+	s.publish(events)
+}
+
+// publish hands events over to the watchers.
+func (s *Seer) publish(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
+
+	for _, w := range s.watchers {
+		w.seen(events)
+		w.push(events...)
+	}
+}
+
+func (s *Seer) unwatch(w *watcher) {
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
+
+	for i, o := range s.watchers {
+		if o == w {
+			s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+// created returns the events reporting the creation of the file or folder at path.
+func (s *Seer) created(path string) []Event {
+	return s.walkEvents(Created, path)
+}
+
+// deleted returns the events reporting the deletion of the file or folder at path.
+func (s *Seer) deleted(path string) []Event {
+	return s.walkEvents(Deleted, path)
+}
+
+func (s *Seer) walkEvents(typ EventType, path string) []Event {
+	events := make([]Event, 0)
+	afero.Walk(s.fs, path, func(p string, info fs.FileInfo, err error) error {
+		if err == nil && (info.IsDir() || s.isDocumentFile(info.Name())) {
+			events = append(events, newEvent(typ, p, info.IsDir()))
+		}
+		return nil
+	})
+	return events
+}
@@ -0,0 +1,91 @@
+package seer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"gotest.tools/v3/assert"
+)
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+	return Event{}
+}
+
+func TestWatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	seer, err := New(VirtualFS(fs, "/"), PollInterval(10*time.Millisecond))
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := seer.Watch(ctx, "cars/*")
+	assert.NilError(t, err)
+
+	// changes made through the seer
+	assert.NilError(t, seer.Path("cars/car.name").Set("tau").Commit())
+	assert.Equal(t, nextEvent(t, events), Event{Type: Created, Path: "cars/car", Kind: KindDocument})
+	assert.Equal(t, nextEvent(t, events), Event{Type: Modified, Path: "cars/car", Kind: KindDocument})
+
+	assert.NilError(t, seer.Sync())
+	assert.Equal(t, nextEvent(t, events), Event{Type: Modified, Path: "cars/car", Kind: KindDocument})
+
+	// rolled back changes are not reported
+	err = seer.Batch(seer.Path("cars/van.name").Set("van"), seer.Path("cars/car.name").RemoveAt(5)).Commit()
+	assert.Assert(t, err != nil)
+
+	assert.NilError(t, seer.Path("cars/car").Delete().Commit())
+	assert.Equal(t, nextEvent(t, events), Event{Type: Deleted, Path: "cars/car", Kind: KindDocument})
+
+	// changes made by others
+	afero.WriteFile(fs, "/cars/bus.yaml", []byte("name: bus\n"), 0640)
+	assert.Equal(t, nextEvent(t, events), Event{Type: Created, Path: "cars/bus", Kind: KindDocument})
+
+	afero.WriteFile(fs, "/cars/bus.yaml", []byte("name: big bus\n"), 0640)
+	assert.Equal(t, nextEvent(t, events), Event{Type: Modified, Path: "cars/bus", Kind: KindDocument})
+
+	fs.Remove("/cars/bus.yaml")
+	assert.Equal(t, nextEvent(t, events), Event{Type: Deleted, Path: "cars/bus", Kind: KindDocument})
+
+	cancel()
+	for range events {
+	}
+	assert.Equal(t, len(seer.watchers), 0)
+}
+
+func TestWatchSystemFS(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "cars"), 0750))
+
+	seer, err := New(SystemFS(dir))
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := seer.Watch(ctx, "")
+	assert.NilError(t, err)
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "cars", "car.yaml"), []byte("name: tau\n"), 0640))
+	assert.Equal(t, nextEvent(t, events), Event{Type: Created, Path: "cars/car", Kind: KindDocument})
+
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "bikes"), 0750))
+	assert.Equal(t, nextEvent(t, events), Event{Type: Created, Path: "bikes", Kind: KindFolder})
+
+	// new folders are watched too
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "bikes", "bmx.yaml"), []byte("name: bmx\n"), 0640))
+	assert.Equal(t, nextEvent(t, events), Event{Type: Created, Path: "bikes/bmx", Kind: KindDocument})
+}
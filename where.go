@@ -0,0 +1,152 @@
+package seer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Where appends a segment matching the children of a folder, document, mapping or sequence
+// whose field compares to value with the operator op, which is one of
+// `==`, `!=`, `<`, `<=`, `>`, `>=`, `in` and `contains`.
+//
+// field is a path expression relative to the child, like `spec.enabled`. Children
+// missing the field do not match. Like Glob, the query is evaluated with Matches().
+func (n *Query) Where(field string, op string, value interface{}) *Query {
+	compare, ok := operators[op]
+	if !ok {
+		n.errors = append(n.errors, fmt.Errorf("unknown operator `%s`", op))
+		return n
+	}
+
+	segments, err := parsePath(field)
+	if err != nil {
+		n.errors = append(n.errors, err)
+		return n
+	}
+
+	expected, err := normalize(value)
+	if err != nil {
+		n.errors = append(n.errors, fmt.Errorf("invalid value for `%s %s`: %w", field, op, err))
+		return n
+	}
+
+	return n.Filter(func(name string, q *Query) bool {
+		for _, seg := range segments {
+			q.Get(seg.name)
+		}
+
+		var actual interface{}
+		if q.Value(&actual) != nil {
+			return false
+		}
+
+		return compare(actual, expected)
+	})
+}
+
+// Filter appends a segment matching the children of a folder, document, mapping or sequence
+// for which fn returns true. fn is given the name of the child and a query pointing to it,
+// which can only be used during the call. Like Glob, the query is evaluated with Matches().
+func (n *Query) Filter(fn func(name string, q *Query) bool) *Query {
+	n.requestedPath = append(n.requestedPath, "*")
+	n.ops = append(n.ops,
+		op{
+			opType:  opTypeGlob,
+			name:    "*",
+			filter:  fn,
+			handler: opGlob,
+		},
+	)
+	return n
+}
+
+var operators = map[string]func(actual, expected interface{}) bool{
+	"==": equal,
+	"!=": func(a, e interface{}) bool { return !equal(a, e) },
+	"<":  func(a, e interface{}) bool { c, ok := order(a, e); return ok && c < 0 },
+	"<=": func(a, e interface{}) bool { c, ok := order(a, e); return ok && c <= 0 },
+	">":  func(a, e interface{}) bool { c, ok := order(a, e); return ok && c > 0 },
+	">=": func(a, e interface{}) bool { c, ok := order(a, e); return ok && c >= 0 },
+	"in": func(a, e interface{}) bool {
+		list, ok := e.([]interface{})
+		return ok && contains(list, a)
+	},
+	"contains": func(a, e interface{}) bool {
+		switch a := a.(type) {
+		case []interface{}:
+			return contains(a, e)
+		case string:
+			s, ok := e.(string)
+			return ok && strings.Contains(a, s)
+		}
+		return false
+	},
+}
+
+// normalize converts value to what decoding its YAML representation into an interface{} gives.
+func normalize(value interface{}) (interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	err := node.Decode(&out)
+	return out, err
+}
+
+func equal(a, b interface{}) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func order(a, b interface{}) (int, bool) {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		switch {
+		case !ok:
+			return 0, false
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if sa, ok := a.(string); ok {
+		sb, ok := b.(string)
+		return strings.Compare(sa, sb), ok
+	}
+
+	return 0, false
+}
+
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func contains(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if equal(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package seer
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWhere(t *testing.T) {
+	seer, err := New(fixtureFS(true, "/"))
+	assert.NilError(t, err)
+
+	type service struct {
+		Enabled bool
+		Port    int
+		Tags    []string
+	}
+
+	for name, svc := range map[string]service{
+		"api":    {Enabled: true, Port: 443, Tags: []string{"public"}},
+		"db":     {Enabled: true, Port: 5432},
+		"legacy": {Enabled: false, Port: 80, Tags: []string{"public"}},
+	} {
+		assert.NilError(t, seer.Get("services").Get(name).Document().Set(svc).Commit())
+	}
+
+	for _, tc := range []struct {
+		field    string
+		op       string
+		value    interface{}
+		expected []string
+	}{
+		{"enabled", "==", true, []string{"api", "db"}},
+		{"enabled", "!=", true, []string{"legacy"}},
+		{"port", ">", 100, []string{"api", "db"}},
+		{"port", "<=", 443.0, []string{"api", "legacy"}},
+		{"port", "in", []int{80, 5432}, []string{"db", "legacy"}},
+		{"tags", "contains", "public", []string{"api", "legacy"}},
+		{"missing", "==", nil, []string{}},
+	} {
+		matches, err := seer.Get("services").Where(tc.field, tc.op, tc.value).Matches()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, matches.Names(), tc.expected)
+	}
+
+	assert.Equal(t, len(seer.Get("services").Where("port", "~", 1).Errors()), 1)
+
+	matches, err := seer.Get("services").Filter(func(name string, q *Query) bool {
+		var port int
+		return q.Get("port").Value(&port) == nil && port%2 == 0
+	}).Get("port").Matches()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, matches.Paths(), []string{"services/db.port", "services/legacy.port"})
+
+	assert.NilError(t, seer.Get("services").Where("enabled", "==", false).Get("enabled").Set(true).Commit())
+	matches, err = seer.Get("services").Where("enabled", "==", true).Matches()
+	assert.NilError(t, err)
+	assert.Equal(t, matches.Len(), 3)
+}
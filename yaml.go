@@ -0,0 +1,38 @@
+package seer
+
+import "gopkg.in/yaml.v3"
+
+// cloneNode returns a deep copy of node. Aliases inside the copy point to the copied anchors.
+func cloneNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	copies := make(map[*yaml.Node]*yaml.Node)
+	clone := _cloneNode(node, copies)
+	_relinkAliases(clone, copies)
+	return clone
+}
+
+func _cloneNode(node *yaml.Node, copies map[*yaml.Node]*yaml.Node) *yaml.Node {
+	n := *node
+	copies[node] = &n
+	if len(node.Content) > 0 {
+		n.Content = make([]*yaml.Node, len(node.Content))
+		for i, c := range node.Content {
+			n.Content[i] = _cloneNode(c, copies)
+		}
+	}
+	return &n
+}
+
+func _relinkAliases(node *yaml.Node, copies map[*yaml.Node]*yaml.Node) {
+	if node.Alias != nil {
+		if target, ok := copies[node.Alias]; ok {
+			node.Alias = target
+		}
+	}
+	for _, c := range node.Content {
+		_relinkAliases(c, copies)
+	}
+}